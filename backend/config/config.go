@@ -0,0 +1,328 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"tenant-center/utils"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// 环境变量前缀，例如 TENANT_DATABASE_DSN
+const envPrefix = "TENANT_"
+
+// 未指定配置文件时依次尝试的默认路径
+var defaultConfigFiles = []string{"config.yaml", "config.yml", "config.toml"}
+
+// Config 应用配置
+type Config struct {
+	Server   ServerConfig   `yaml:"server" toml:"server"`
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	JWT      JWTConfig      `yaml:"jwt" toml:"jwt"`
+	Password PasswordConfig `yaml:"password" toml:"password"`
+	Authz    AuthzConfig    `yaml:"authz" toml:"authz"`
+}
+
+// ServerConfig HTTP服务配置
+type ServerConfig struct {
+	Port int    `yaml:"port" toml:"port"`
+	Mode string `yaml:"mode" toml:"mode"` // gin运行模式：debug、release、test
+}
+
+// Addr 返回监听地址
+func (s ServerConfig) Addr() string {
+	return ":" + strconv.Itoa(s.Port)
+}
+
+// DatabaseConfig 数据库配置
+type DatabaseConfig struct {
+	DSN             string   `yaml:"dsn" toml:"dsn"`
+	MaxOpenConns    int      `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int      `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	AutoMigrate     bool     `yaml:"auto_migrate" toml:"auto_migrate"` // 启动时同步表结构
+}
+
+// JWTConfig JWT签发配置
+type JWTConfig struct {
+	Expire        Duration           `yaml:"expire" toml:"expire"`                 // 访问令牌有效期
+	RefreshExpire Duration           `yaml:"refresh_expire" toml:"refresh_expire"` // 刷新令牌有效期
+	Keys          []SigningKeyConfig `yaml:"keys" toml:"keys"`                     // 签名密钥，为空时使用进程内临时密钥（仅限debug/test模式）
+}
+
+// SigningKeyConfig 签名密钥配置，通过 not_before/not_after 安排密钥轮换
+type SigningKeyConfig struct {
+	ID             string    `yaml:"id" toml:"id"`                             // JWT头部的 kid
+	PrivateKeyFile string    `yaml:"private_key_file" toml:"private_key_file"` // PEM格式的RSA或Ed25519私钥
+	NotBefore      time.Time `yaml:"not_before" toml:"not_before"`             // 开始用于签名的时间，可选
+	NotAfter       time.Time `yaml:"not_after" toml:"not_after"`               // 停止用于签名的时间，可选
+}
+
+// PasswordConfig 密码哈希配置
+type PasswordConfig struct {
+	Algorithm string `yaml:"algorithm" toml:"algorithm"` // bcrypt 或 argon2id
+}
+
+// 权限授予模式，决定角色被授予层级权限中的某个节点时，其上下级权限是否随之生效
+const (
+	GrantModeExact     = "exact"     // 只授予直接绑定的权限
+	GrantModeSubtree   = "subtree"   // 授予父权限即授予其全部子孙权限
+	GrantModeAncestors = "ancestors" // 授予子权限时自动包含其全部上级权限
+)
+
+// AuthzConfig 权限校验配置
+type AuthzConfig struct {
+	SuperRole string `yaml:"super_role" toml:"super_role"` // 超级管理员角色编码，拥有全部权限，为空表示不启用
+	GrantMode string `yaml:"grant_mode" toml:"grant_mode"` // 层级权限的授予模式：exact、subtree、ancestors
+	// CachePollInterval 轮询策略版本的间隔，其他实例的修改最迟在一个间隔后生效，为 0 时不启用进程内权限缓存
+	CachePollInterval Duration `yaml:"cache_poll_interval" toml:"cache_poll_interval"`
+}
+
+// Duration 支持 "24h"、"30m" 格式的时间间隔
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口，YAML和TOML解析均会使用
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Default 返回默认配置
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port: 8080,
+			Mode: "debug",
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    50,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: Duration{time.Hour},
+			AutoMigrate:     true,
+		},
+		JWT: JWTConfig{
+			Expire:        Duration{15 * time.Minute},
+			RefreshExpire: Duration{7 * 24 * time.Hour},
+		},
+		Password: PasswordConfig{
+			Algorithm: utils.AlgorithmBcrypt,
+		},
+		Authz: AuthzConfig{
+			GrantMode:         GrantModeExact,
+			CachePollInterval: Duration{2 * time.Second},
+		},
+	}
+}
+
+// Load 加载配置，优先级从低到高依次为：默认值、配置文件、环境变量、命令行参数
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("tenant-center", flag.ContinueOnError)
+	configFile := fs.String("config", "", "配置文件路径（.yaml/.yml/.toml）")
+	port := fs.Int("port", 0, "HTTP监听端口")
+	dsn := fs.String("dsn", "", "MySQL连接串")
+	mode := fs.String("mode", "", "gin运行模式：debug、release、test")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+
+	path := *configFile
+	if path == "" {
+		path = os.Getenv(envPrefix + "CONFIG")
+	}
+	if path == "" {
+		path = findDefaultConfigFile()
+	}
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	// 只覆盖命令行中显式指定的参数
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Server.Port = *port
+		case "dsn":
+			cfg.Database.DSN = *dsn
+		case "mode":
+			cfg.Server.Mode = *mode
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// findDefaultConfigFile 查找工作目录下的默认配置文件
+func findDefaultConfigFile() string {
+	for _, name := range defaultConfigFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// loadFile 根据扩展名解析配置文件
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("不支持的配置文件格式: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+	return nil
+}
+
+// envBinding 环境变量与配置项的对应关系
+type envBinding struct {
+	name  string
+	apply func(cfg *Config, value string) error
+}
+
+var envBindings = []envBinding{
+	{"SERVER_PORT", func(cfg *Config, v string) error { return parseInt(v, &cfg.Server.Port) }},
+	{"SERVER_MODE", func(cfg *Config, v string) error { cfg.Server.Mode = v; return nil }},
+	{"DATABASE_DSN", func(cfg *Config, v string) error { cfg.Database.DSN = v; return nil }},
+	{"DATABASE_MAX_OPEN_CONNS", func(cfg *Config, v string) error { return parseInt(v, &cfg.Database.MaxOpenConns) }},
+	{"DATABASE_MAX_IDLE_CONNS", func(cfg *Config, v string) error { return parseInt(v, &cfg.Database.MaxIdleConns) }},
+	{"DATABASE_CONN_MAX_LIFETIME", func(cfg *Config, v string) error { return cfg.Database.ConnMaxLifetime.UnmarshalText([]byte(v)) }},
+	{"DATABASE_AUTO_MIGRATE", func(cfg *Config, v string) error { return parseBool(v, &cfg.Database.AutoMigrate) }},
+	{"JWT_EXPIRE", func(cfg *Config, v string) error { return cfg.JWT.Expire.UnmarshalText([]byte(v)) }},
+	{"JWT_REFRESH_EXPIRE", func(cfg *Config, v string) error { return cfg.JWT.RefreshExpire.UnmarshalText([]byte(v)) }},
+	{"PASSWORD_ALGORITHM", func(cfg *Config, v string) error { cfg.Password.Algorithm = v; return nil }},
+	{"AUTHZ_SUPER_ROLE", func(cfg *Config, v string) error { cfg.Authz.SuperRole = v; return nil }},
+	{"AUTHZ_GRANT_MODE", func(cfg *Config, v string) error { cfg.Authz.GrantMode = v; return nil }},
+	{"AUTHZ_CACHE_POLL_INTERVAL", func(cfg *Config, v string) error { return cfg.Authz.CachePollInterval.UnmarshalText([]byte(v)) }},
+}
+
+// applyEnv 使用环境变量覆盖配置
+func applyEnv(cfg *Config) error {
+	for _, b := range envBindings {
+		value, ok := os.LookupEnv(envPrefix + b.name)
+		if !ok {
+			continue
+		}
+		if err := b.apply(cfg, value); err != nil {
+			return fmt.Errorf("环境变量 %s%s 无效: %w", envPrefix, b.name, err)
+		}
+	}
+	return nil
+}
+
+func parseInt(value string, target *int) error {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*target = v
+	return nil
+}
+
+func parseBool(value string, target *bool) error {
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*target = v
+	return nil
+}
+
+// Validate 校验配置
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port 超出范围: %d", c.Server.Port))
+	}
+	switch c.Server.Mode {
+	case "debug", "release", "test":
+	default:
+		errs = append(errs, fmt.Errorf("server.mode 无效: %s", c.Server.Mode))
+	}
+
+	if c.Database.DSN == "" {
+		errs = append(errs, errors.New("database.dsn 不能为空"))
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database 连接池大小不能为负数"))
+	}
+
+	if len(c.JWT.Keys) == 0 && c.Server.Mode == "release" {
+		errs = append(errs, errors.New("release 模式下必须配置 jwt.keys"))
+	}
+	keyIDs := make(map[string]bool)
+	for _, key := range c.JWT.Keys {
+		if key.ID == "" || key.PrivateKeyFile == "" {
+			errs = append(errs, errors.New("jwt.keys 的 id 和 private_key_file 不能为空"))
+			continue
+		}
+		if keyIDs[key.ID] {
+			errs = append(errs, fmt.Errorf("jwt.keys 的 id 重复: %s", key.ID))
+		}
+		keyIDs[key.ID] = true
+		if !key.NotAfter.IsZero() && !key.NotAfter.After(key.NotBefore) {
+			errs = append(errs, fmt.Errorf("jwt.keys[%s] 的 not_after 必须晚于 not_before", key.ID))
+		}
+	}
+	if c.JWT.Expire.Duration <= 0 {
+		errs = append(errs, errors.New("jwt.expire 必须大于0"))
+	}
+	if c.JWT.RefreshExpire.Duration <= c.JWT.Expire.Duration {
+		errs = append(errs, errors.New("jwt.refresh_expire 必须大于 jwt.expire"))
+	}
+
+	switch c.Password.Algorithm {
+	case utils.AlgorithmBcrypt, utils.AlgorithmArgon2id:
+	default:
+		errs = append(errs, fmt.Errorf("password.algorithm 不支持: %s", c.Password.Algorithm))
+	}
+
+	switch c.Authz.GrantMode {
+	case GrantModeExact, GrantModeSubtree, GrantModeAncestors:
+	default:
+		errs = append(errs, fmt.Errorf("authz.grant_mode 不支持: %s", c.Authz.GrantMode))
+	}
+	if c.Authz.CachePollInterval.Duration < 0 {
+		errs = append(errs, errors.New("authz.cache_poll_interval 不能小于0"))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"tenant-center/models"
+	"tenant-center/services"
+)
+
+// @title 审计日志API
+// @version 1.0
+// @description 审计日志相关的API接口，用于查询用户、角色、权限、菜单、按钮和租户的变更记录
+
+// AuditController 审计日志控制器
+type AuditController struct {
+	auditService *services.AuditService
+}
+
+// NewAuditController 创建审计日志控制器实例
+func NewAuditController(db *gorm.DB) *AuditController {
+	return &AuditController{
+		auditService: services.NewAuditService(db),
+	}
+}
+
+// GetAuditLogsRequest 获取审计日志列表请求参数
+type GetAuditLogsRequest struct {
+	ListRequest
+	ActorID      *int    `json:"actor_id" example:"1"`         // 操作人ID
+	ResourceType *string `json:"resource_type" example:"role"` // 资源类型：user、role、permission、menu、button、tenant
+	ResourceID   *int    `json:"resource_id" example:"1"`      // 资源ID
+	Action       *string `json:"action" example:"update"`      // 操作类型
+	RequestID    *string `json:"request_id" example:""`        // 请求ID
+}
+
+// GetAuditLogsResponse 审计日志列表响应
+type GetAuditLogsResponse struct {
+	Data       []models.AuditLog `json:"data"`
+	Total      int64             `json:"total"` // 游标分页时不返回
+	Page       int               `json:"page"`
+	PageSize   int               `json:"pageSize"`
+	NextCursor string            `json:"next_cursor,omitempty"` // 游标分页的下一页游标，为空表示没有更多数据
+}
+
+// PageAuditLogs @Summary 获取审计日志列表
+// @Description 按操作人、资源、操作类型、请求ID和时间范围筛选审计日志，支持分页和排序，默认按时间倒序
+// @Tags 审计日志
+// @Accept json
+// @Produce json
+// @Param request body GetAuditLogsRequest true "筛选和分页参数"
+// @Success 200 {object} GetAuditLogsResponse "审计日志列表"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 500 {object} ErrorResponse "获取审计日志失败"
+// @Security ApiKeyAuth
+// @Router /api/audit-logs/page [post]
+func (c *AuditController) PageAuditLogs(ctx *gin.Context) {
+	var req GetAuditLogsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	query := req.toQuery(map[string]interface{}{
+		"actor_id":      optionalInt(req.ActorID),
+		"resource_type": optionalString(req.ResourceType),
+		"resource_id":   optionalInt(req.ResourceID),
+		"action":        optionalString(req.Action),
+		"request_id":    optionalString(req.RequestID),
+	})
+	logs, info, err := c.auditService.WithContext(ctx.Request.Context()).PageAuditLogs(query)
+	if err != nil {
+		respondListError(ctx, err, "获取审计日志失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listResponse(logs, req.ListRequest, info))
+}
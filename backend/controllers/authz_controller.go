@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"tenant-center/config"
+	"tenant-center/models"
+	"tenant-center/services"
+)
+
+// @title 权限判定API
+// @version 1.0
+// @description 供下游服务查询用户是否拥有权限，判定规则与本服务接口的权限校验一致；并可解释用户获得或被拒绝某权限的原因
+
+// AuthzController 权限判定控制器
+type AuthzController struct {
+	authzService *services.AuthzService
+}
+
+// NewAuthzController 创建权限判定控制器实例
+func NewAuthzController(db *gorm.DB, cfg *config.Config) *AuthzController {
+	return &AuthzController{
+		authzService: services.NewAuthzService(db, cfg),
+	}
+}
+
+// AuthzCheckRequest 权限判定请求参数
+type AuthzCheckRequest struct {
+	Subject    int    `json:"subject" binding:"required,min=1" example:"1"`        // 用户ID
+	Permission string `json:"permission" binding:"required" example:"user:create"` // 权限编码
+	TenantID   int    `json:"tenant_id" example:"1"`                               // 用户所属租户，不传时为调用方所在租户
+	Resource   string `json:"resource" example:"order:1024"`                       // 资源标识，目前仅原样返回，判定只依据权限编码
+}
+
+// AuthzBatchCheckRequest 批量权限判定请求参数
+type AuthzBatchCheckRequest struct {
+	Checks []AuthzCheckRequest `json:"checks" binding:"required,min=1,max=100,dive"` // 判定请求，最多100条
+}
+
+// AuthzBatchCheckResponse 批量权限判定响应
+type AuthzBatchCheckResponse struct {
+	Results []services.AuthzResult `json:"results"` // 与请求一一对应
+}
+
+// Check @Summary 权限判定
+// @Description 判定用户是否拥有指定权限，返回是否允许、判定原因和决定结果的角色。reason 为 super_admin、granted、denied（被角色显式拒绝）、not_granted、tenant_inactive 或 subject_not_found。非平台租户只能判定本租户的用户
+// @Tags 权限判定
+// @Accept json
+// @Produce json
+// @Param request body AuthzCheckRequest true "判定请求"
+// @Success 200 {object} services.AuthzResult "判定结果"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 403 {object} ErrorResponse "无权判定其他租户的用户"
+// @Failure 500 {object} ErrorResponse "权限判定失败"
+// @Security ApiKeyAuth
+// @Router /api/authz/check [post]
+func (c *AuthzController) Check(ctx *gin.Context) {
+	var req AuthzCheckRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	results, ok := c.check(ctx, []AuthzCheckRequest{req})
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, results[0])
+}
+
+// BatchCheck @Summary 批量权限判定
+// @Description 一次判定多条权限请求，结果顺序与请求一致，同一用户的请求只加载一次授权数据。判定规则与单条判定相同
+// @Tags 权限判定
+// @Accept json
+// @Produce json
+// @Param request body AuthzBatchCheckRequest true "判定请求列表"
+// @Success 200 {object} AuthzBatchCheckResponse "判定结果"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 403 {object} ErrorResponse "无权判定其他租户的用户"
+// @Failure 500 {object} ErrorResponse "权限判定失败"
+// @Security ApiKeyAuth
+// @Router /api/authz/check/batch [post]
+func (c *AuthzController) BatchCheck(ctx *gin.Context) {
+	var req AuthzBatchCheckRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	results, ok := c.check(ctx, req.Checks)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, AuthzBatchCheckResponse{Results: results})
+}
+
+// check 补全租户并校验调用方可以判定该租户，失败时已写入响应
+func (c *AuthzController) check(ctx *gin.Context, reqs []AuthzCheckRequest) ([]services.AuthzResult, bool) {
+	callerTenantID := ctx.GetInt("tenant_id")
+	checks := make([]services.AuthzCheck, len(reqs))
+	for i, req := range reqs {
+		tenantID := req.TenantID
+		if tenantID == 0 {
+			tenantID = callerTenantID
+		}
+		if tenantID != callerTenantID && callerTenantID != models.DefaultTenantID {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "无权判定其他租户的用户"})
+			return nil, false
+		}
+		checks[i] = services.AuthzCheck{
+			Subject:    req.Subject,
+			TenantID:   tenantID,
+			Permission: req.Permission,
+			Resource:   req.Resource,
+		}
+	}
+
+	results, err := c.authzService.WithContext(ctx.Request.Context()).Check(checks)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "权限判定失败"})
+		return nil, false
+	}
+	return results, true
+}
+
+// AuthzExplainRequest 授权解释请求参数，permission、menu_id、button_id 只能指定一个
+type AuthzExplainRequest struct {
+	Subject    int    `json:"subject" binding:"required,min=1" example:"1"` // 用户ID
+	Permission string `json:"permission" example:"user:create"`             // 权限编码
+	MenuID     int    `json:"menu_id" example:"1"`                          // 菜单ID
+	ButtonID   int    `json:"button_id" example:"1"`                        // 按钮ID
+}
+
+// Explain @Summary 解释用户的授权
+// @Description 给定用户和权限编码、菜单ID或按钮ID之一，列出授予或拒绝它的全部路径：用户角色 →（继承的角色）→ 绑定的权限 →（通配匹配、上下级展开得到的权限），并给出最终结果和起决定作用的角色。拒绝路径在前，被拒绝覆盖的授予路径 outcome 为 overridden。菜单的任一子孙菜单被授权时菜单同样可见，相应路径一并列出
+// @Tags 权限判定
+// @Accept json
+// @Produce json
+// @Param request body AuthzExplainRequest true "解释请求"
+// @Success 200 {object} services.Explanation "授权路径"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 404 {object} ErrorResponse "用户不存在"
+// @Failure 500 {object} ErrorResponse "解释授权失败"
+// @Security ApiKeyAuth
+// @Router /api/authz/explain [post]
+func (c *AuthzController) Explain(ctx *gin.Context) {
+	var req AuthzExplainRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	target := services.ExplainTarget{Permission: req.Permission, MenuID: req.MenuID, ButtonID: req.ButtonID}
+	explanation, err := c.authzService.WithContext(ctx.Request.Context()).Explain(req.Subject, target)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		case errors.Is(err, services.ErrInvalidExplainTarget):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "解释授权失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, explanation)
+}
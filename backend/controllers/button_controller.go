@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
 	"strconv"
+	"tenant-center/config"
 	"tenant-center/models"
 	"tenant-center/services"
 )
@@ -19,9 +21,9 @@ type ButtonController struct {
 }
 
 // NewButtonController 创建按钮控制器实例
-func NewButtonController(db *gorm.DB) *ButtonController {
+func NewButtonController(db *gorm.DB, cfg *config.Config) *ButtonController {
 	return &ButtonController{
-		buttonService: services.NewButtonService(db),
+		buttonService: services.NewButtonService(db, cfg),
 	}
 }
 
@@ -71,6 +73,7 @@ type BindButtonPermissionResponse struct {
 // @Produce json
 // @Param id path int true "按钮ID"
 // @Success 200 {object} models.Button "按钮详情"
+// @Header 200 {string} ETag "资源版本，修改时通过 If-Match 提交"
 // @Failure 400 {object} ErrorResponse "无效的按钮ID"
 // @Failure 500 {object} ErrorResponse "获取按钮详情失败"
 // @Security ApiKeyAuth
@@ -82,12 +85,13 @@ func (c *ButtonController) GetDetail(ctx *gin.Context) {
 		return
 	}
 
-	button, err := c.buttonService.GetButtonByID(buttonID)
+	button, err := c.buttonService.WithContext(ctx.Request.Context()).GetButtonByID(buttonID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取按钮详情失败"})
 		return
 	}
 
+	ctx.Header("ETag", versionETag(button.Version))
 	ctx.JSON(http.StatusOK, button)
 }
 
@@ -101,7 +105,7 @@ type GetButtonPermissionsResponse []models.Permission
 // @Produce json
 // @Param button body CreateButtonRequest true "按钮信息"
 // @Success 201 {object} CreateButtonResponse "按钮创建成功"
-// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 400 {object} ErrorResponse "无效的请求参数或所属菜单不存在"
 // @Failure 500 {object} ErrorResponse "创建按钮失败"
 // @Security ApiKeyAuth
 // @Router /api/buttons [post]
@@ -124,7 +128,11 @@ func (c *ButtonController) CreateButton(ctx *gin.Context) {
 		MenuID:         button.MenuID,
 	}
 
-	if err := c.buttonService.CreateButton(newButton); err != nil {
+	if err := c.buttonService.WithContext(ctx.Request.Context()).CreateButton(newButton); err != nil {
+		if errors.Is(err, services.ErrMenuNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "创建按钮失败"})
 		return
 	}
@@ -139,8 +147,11 @@ func (c *ButtonController) CreateButton(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "按钮ID"
 // @Param button body UpdateButtonRequest true "按钮信息"
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
 // @Success 200 {object} UpdateButtonResponse "按钮信息更新成功"
-// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 400 {object} ErrorResponse "无效的请求参数或所属菜单不存在"
+// @Failure 404 {object} ErrorResponse "按钮不存在"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
 // @Failure 500 {object} ErrorResponse "更新按钮信息失败"
 // @Security ApiKeyAuth
 // @Router /api/buttons/{id} [put]
@@ -163,18 +174,34 @@ func (c *ButtonController) UpdateButton(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
 	button := &models.Button{
 		ID:             buttonID,
 		Name:           updateData.Name,
 		PermissionCode: updateData.Code,
 		MenuID:         updateData.MenuID,
+		Version:        version,
 	}
 
-	if err := c.buttonService.UpdateButton(button); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新按钮信息失败"})
+	if err := c.buttonService.WithContext(ctx.Request.Context()).UpdateButton(button); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "按钮不存在"})
+		case errors.Is(err, services.ErrMenuNotFound):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新按钮信息失败"})
+		}
 		return
 	}
 
+	ctx.Header("ETag", versionETag(button.Version))
 	ctx.JSON(http.StatusOK, gin.H{"message": "按钮信息更新成功"})
 }
 
@@ -197,7 +224,7 @@ func (c *ButtonController) GetButtonsByMenuID(ctx *gin.Context) {
 		return
 	}
 
-	buttons, err := c.buttonService.GetButtonsByMenuID(menuID)
+	buttons, err := c.buttonService.WithContext(ctx.Request.Context()).GetButtonsByMenuID(menuID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取按钮列表失败"})
 		return
@@ -208,16 +235,17 @@ func (c *ButtonController) GetButtonsByMenuID(ctx *gin.Context) {
 
 // GetButtonsRequest 获取按钮列表请求参数
 type GetButtonsRequest struct {
-	Page     int `json:"page" example:"1" binding:"required"`
-	PageSize int `json:"pageSize" example:"10" binding:"required"`
+	ListRequest
+	MenuID *int `json:"menu_id" example:"1"` // 按所属菜单筛选
 }
 
 // GetButtonsResponse 按钮列表响应
 type GetButtonsResponse struct {
-	Data     []models.Button `json:"data"`
-	Total    int64           `json:"total"`
-	Page     int             `json:"page"`
-	PageSize int             `json:"pageSize"`
+	Data       []models.Button `json:"data"`
+	Total      int64           `json:"total"` // 游标分页时不返回
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	NextCursor string          `json:"next_cursor,omitempty"` // 游标分页的下一页游标，为空表示没有更多数据
 }
 
 // ListButtons @Summary 获取按钮列表
@@ -230,7 +258,7 @@ type GetButtonsResponse struct {
 // @Failure 400 {object} ErrorResponse "无效的请求参数"
 // @Failure 500 {object} ErrorResponse "获取按钮列表失败"
 // @Security ApiKeyAuth
-// @Router /api/buttons [post]
+// @Router /api/buttons/page [post]
 // ListButtons 获取按钮列表
 func (c *ButtonController) ListButtons(ctx *gin.Context) {
 	var req GetButtonsRequest
@@ -239,18 +267,16 @@ func (c *ButtonController) ListButtons(ctx *gin.Context) {
 		return
 	}
 
-	buttons, total, err := c.buttonService.ListButtons(req.Page, req.PageSize)
+	query := req.toQuery(map[string]interface{}{
+		"menu_id": optionalInt(req.MenuID),
+	})
+	buttons, info, err := c.buttonService.WithContext(ctx.Request.Context()).ListButtons(query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取按钮列表失败"})
+		respondListError(ctx, err, "获取按钮列表失败")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"data":     buttons,
-		"total":    total,
-		"page":     req.Page,
-		"pageSize": req.PageSize,
-	})
+	ctx.JSON(http.StatusOK, listResponse(buttons, req.ListRequest, info))
 }
 
 // BindPermission @Summary 为按钮绑定权限
@@ -283,7 +309,11 @@ func (c *ButtonController) BindPermission(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.buttonService.BindButtonPermission(buttonID, permission.PermissionCode, permission.Name); err != nil {
+	if err := c.buttonService.WithContext(ctx.Request.Context()).BindButtonPermission(buttonID, permission.PermissionCode, permission.Name); err != nil {
+		if errors.Is(err, services.ErrInvalidPermissionCode) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "绑定权限失败"})
 		return
 	}
@@ -310,7 +340,7 @@ func (c *ButtonController) GetButtonPermissions(ctx *gin.Context) {
 		return
 	}
 
-	permissions, err := c.buttonService.GetButtonPermissions(buttonID)
+	permissions, err := c.buttonService.WithContext(ctx.Request.Context()).GetButtonPermissions(buttonID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取按钮权限列表失败"})
 		return
@@ -318,3 +348,44 @@ func (c *ButtonController) GetButtonPermissions(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, permissions)
 }
+
+// DeleteButton @Summary 删除按钮
+// @Description 删除指定按钮。按钮绑定了权限时默认拒绝删除；传入 cascade=true 时一并删除这些权限及其角色关联。删除后进入回收站，可恢复
+// @Tags 按钮管理
+// @Produce json
+// @Param id path int true "按钮ID"
+// @Param cascade query bool false "是否级联删除"
+// @Success 200 {object} object "按钮删除成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 404 {object} ErrorResponse "按钮不存在"
+// @Failure 409 {object} ErrorResponse "按钮已绑定权限，不能删除"
+// @Failure 500 {object} ErrorResponse "删除按钮失败"
+// @Security ApiKeyAuth
+// @Router /api/buttons/{id} [delete]
+func (c *ButtonController) DeleteButton(ctx *gin.Context) {
+	buttonID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的按钮ID"})
+		return
+	}
+
+	cascade, err := strconv.ParseBool(ctx.DefaultQuery("cascade", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	if err := c.buttonService.WithContext(ctx.Request.Context()).DeleteButton(buttonID, cascade); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "按钮不存在"})
+		case errors.Is(err, services.ErrButtonInUse):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "删除按钮失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "按钮删除成功"})
+}
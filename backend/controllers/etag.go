@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"tenant-center/services"
+)
+
+// etagMatches 判断 If-None-Match 请求头是否包含指定 ETag，支持逗号分隔的多个值、弱校验前缀和 *
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// versionETag 将资源版本格式化为 ETag
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ifMatchVersion 解析 If-Match 请求头中的资源版本，未传或为 * 时返回 0 表示不校验
+//
+// If-Match 只做强比较且这里只接受单个 ETag，无法识别的值不可能与当前版本一致，直接响应 412 并返回 false。
+func ifMatchVersion(ctx *gin.Context) (int, bool) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	if len(header) > 2 && strings.HasPrefix(header, `"`) && strings.HasSuffix(header, `"`) {
+		if version, err := strconv.Atoi(header[1 : len(header)-1]); err == nil && version > 0 {
+			return version, true
+		}
+	}
+	respondVersionConflict(ctx)
+	return 0, false
+}
+
+// respondVersionConflict 响应 412，提示客户端重新获取资源后再修改
+func respondVersionConflict(ctx *gin.Context) {
+	ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": services.ErrVersionConflict.Error()})
+}
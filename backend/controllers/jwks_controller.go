@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"tenant-center/keyring"
+)
+
+// JWKSController 公钥发布控制器
+type JWKSController struct {
+	keyring *keyring.Keyring
+}
+
+// NewJWKSController 创建公钥发布控制器实例
+func NewJWKSController(ring *keyring.Keyring) *JWKSController {
+	return &JWKSController{keyring: ring}
+}
+
+// GetJWKS @Summary 获取令牌校验公钥
+// @Description 以JWKS格式公布校验访问令牌所需的公钥，包括即将轮换启用的密钥，下游服务按令牌头部的kid选择公钥
+// @Tags 认证
+// @Produce json
+// @Success 200 {object} keyring.JWKS "公钥集合"
+// @Router /.well-known/jwks.json [get]
+func (c *JWKSController) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.keyring.JWKS())
+}
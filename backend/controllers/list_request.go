@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"tenant-center/services"
+	"time"
+)
+
+// ListRequest 列表请求的公共参数：分页、关键字、创建时间范围和排序
+//
+// 传入 cursor 时使用游标分页，首页传空字符串，之后传上一页响应中的 next_cursor。
+type ListRequest struct {
+	Page        int        `json:"page" example:"1" binding:"omitempty,min=1"`                                       // 页码分页的页码，默认第一页
+	PageSize    int        `json:"pageSize" example:"10" minimum:"1" maximum:"500" binding:"required,min=1,max=500"` // 每页条数，最大500
+	Keyword     string     `json:"keyword" example:"admin"`                                                          // 关键字，模糊匹配名称、编码等字段
+	CreatedFrom *time.Time `json:"created_from" example:"2024-01-01T00:00:00+08:00"`                                 // 创建时间起（含）
+	CreatedTo   *time.Time `json:"created_to" example:"2024-02-01T00:00:00+08:00"`                                   // 创建时间止（不含）
+	Sort        []string   `json:"sort" example:"-created_at"`                                                       // 排序字段，"-" 前缀表示倒序
+	Cursor      *string    `json:"cursor" example:""`                                                                // 游标分页的游标，游标分页不返回总数
+}
+
+// page 返回页码，未传入时为第一页
+func (r ListRequest) page() int {
+	if r.Page == 0 {
+		return 1
+	}
+	return r.Page
+}
+
+// toQuery 转换为服务层的列表查询条件
+func (r ListRequest) toQuery(filters map[string]interface{}) services.ListQuery {
+	return services.ListQuery{
+		Page:        r.page(),
+		PageSize:    r.PageSize,
+		Keyword:     r.Keyword,
+		Filters:     filters,
+		CreatedFrom: r.CreatedFrom,
+		CreatedTo:   r.CreatedTo,
+		Sort:        r.Sort,
+		Cursor:      r.Cursor,
+	}
+}
+
+// listResponse 组装列表响应，页码分页返回总数和页码，游标分页返回下一页游标
+func listResponse(data interface{}, req ListRequest, info services.PageInfo) gin.H {
+	resp := gin.H{
+		"data":     data,
+		"pageSize": req.PageSize,
+	}
+	if req.Cursor != nil {
+		resp["next_cursor"] = info.NextCursor
+		return resp
+	}
+	resp["page"] = req.page()
+	if info.Total != nil {
+		resp["total"] = *info.Total
+	}
+	return resp
+}
+
+// optionalInt 未传入的筛选条件返回 nil，查询时忽略
+func optionalInt(v *int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// optionalString 未传入的筛选条件返回 nil，查询时忽略
+func optionalString(v *string) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// optionalBool 未传入的筛选条件返回 nil，查询时忽略
+func optionalBool(v *bool) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// respondListError 返回列表查询错误，排序字段、筛选条件或游标不合法时返回400
+func respondListError(ctx *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrInvalidSortField) || errors.Is(err, services.ErrInvalidFilter) ||
+		errors.Is(err, services.ErrInvalidCursor) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -1,17 +1,19 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
 	"strconv"
+	"tenant-center/config"
 	"tenant-center/models"
 	"tenant-center/services"
 )
 
 // @title 菜单管理API
 // @version 1.0
-// @description 菜单管理相关的API接口，包括创建菜单、更新菜单、获取菜单列表、获取子菜单、绑定权限和获取菜单权限等功能
+// @description 菜单管理相关的API接口，包括创建菜单、更新菜单、获取菜单列表、获取菜单树、移动菜单、获取子菜单、绑定权限和获取菜单权限等功能
 
 // MenuController 菜单控制器
 type MenuController struct {
@@ -19,9 +21,9 @@ type MenuController struct {
 }
 
 // NewMenuController 创建菜单控制器实例
-func NewMenuController(db *gorm.DB) *MenuController {
+func NewMenuController(db *gorm.DB, cfg *config.Config) *MenuController {
 	return &MenuController{
-		menuService: services.NewMenuService(db),
+		menuService: services.NewMenuService(db, cfg),
 	}
 }
 
@@ -57,6 +59,12 @@ type UpdateMenuResponse struct {
 // GetMenusByParentIDResponse 获取子菜单列表响应
 type GetMenusByParentIDResponse []models.Menu
 
+// MoveMenuRequest 移动菜单请求参数
+type MoveMenuRequest struct {
+	ParentID int  `json:"parent_id" example:"0"` // 目标上级菜单ID，0 表示根级
+	Position *int `json:"position" example:"0"`  // 在目标同级菜单中的位置，从 0 开始，不传时放在最后
+}
+
 // GetDetail @Summary 获取菜单详情
 // @Description 获取指定菜单的详细信息
 // @Tags 菜单管理
@@ -64,6 +72,7 @@ type GetMenusByParentIDResponse []models.Menu
 // @Produce json
 // @Param id path int true "菜单ID"
 // @Success 200 {object} models.Menu "菜单详情"
+// @Header 200 {string} ETag "资源版本，修改时通过 If-Match 提交"
 // @Failure 400 {object} ErrorResponse "无效的菜单ID"
 // @Failure 500 {object} ErrorResponse "获取菜单详情失败"
 // @Security ApiKeyAuth
@@ -75,12 +84,13 @@ func (c *MenuController) GetDetail(ctx *gin.Context) {
 		return
 	}
 
-	menu, err := c.menuService.GetMenuByID(menuID)
+	menu, err := c.menuService.WithContext(ctx.Request.Context()).GetMenuByID(menuID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取菜单详情失败"})
 		return
 	}
 
+	ctx.Header("ETag", versionETag(menu.Version))
 	ctx.JSON(http.StatusOK, menu)
 }
 
@@ -132,7 +142,7 @@ func (c *MenuController) CreateMenu(ctx *gin.Context) {
 		Order:    menu.Order,
 	}
 
-	if err := c.menuService.CreateMenu(newMenu); err != nil {
+	if err := c.menuService.WithContext(ctx.Request.Context()).CreateMenu(newMenu); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "创建菜单失败"})
 		return
 	}
@@ -147,8 +157,11 @@ func (c *MenuController) CreateMenu(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "菜单ID"
 // @Param menu body UpdateMenuRequest true "菜单信息"
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
 // @Success 200 {object} UpdateMenuResponse "菜单信息更新成功"
-// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 400 {object} ErrorResponse "无效的请求参数或上级菜单不合法"
+// @Failure 404 {object} ErrorResponse "菜单不存在"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
 // @Failure 500 {object} ErrorResponse "更新菜单信息失败"
 // @Security ApiKeyAuth
 // @Router /api/menus/{id} [put]
@@ -173,6 +186,11 @@ func (c *MenuController) UpdateMenu(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
 	menu := &models.Menu{
 		ID:       menuID,
 		Name:     updateData.Name,
@@ -180,28 +198,41 @@ func (c *MenuController) UpdateMenu(ctx *gin.Context) {
 		Icon:     updateData.Icon,
 		ParentID: updateData.ParentID,
 		Order:    updateData.Order,
+		Version:  version,
 	}
 
-	if err := c.menuService.UpdateMenu(menu); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新菜单信息失败"})
+	if err := c.menuService.WithContext(ctx.Request.Context()).UpdateMenu(menu); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "菜单不存在"})
+		case errors.Is(err, services.ErrMenuParentNotFound), errors.Is(err, services.ErrMenuCycle):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新菜单信息失败"})
+		}
 		return
 	}
 
+	ctx.Header("ETag", versionETag(menu.Version))
 	ctx.JSON(http.StatusOK, gin.H{"message": "菜单信息更新成功"})
 }
 
 // GetMenusRequest 获取菜单列表请求参数
 type GetMenusRequest struct {
-	Page     int `json:"page" example:"1" binding:"required"`
-	PageSize int `json:"pageSize" example:"10" binding:"required"`
+	ListRequest
+	ParentID  *int  `json:"parent_id" example:"0"`     // 按父级菜单筛选
+	IsVisible *bool `json:"is_visible" example:"true"` // 按是否可见筛选
 }
 
 // GetMenusResponse 菜单列表响应
 type GetMenusResponse struct {
-	Data     []models.Menu `json:"data"`
-	Total    int64         `json:"total"`
-	Page     int           `json:"page"`
-	PageSize int           `json:"pageSize"`
+	Data       []models.Menu `json:"data"`
+	Total      int64         `json:"total"` // 游标分页时不返回
+	Page       int           `json:"page"`
+	PageSize   int           `json:"pageSize"`
+	NextCursor string        `json:"next_cursor,omitempty"` // 游标分页的下一页游标，为空表示没有更多数据
 }
 
 // ListMenus @Summary 获取菜单列表
@@ -214,7 +245,7 @@ type GetMenusResponse struct {
 // @Failure 400 {object} ErrorResponse "无效的请求参数"
 // @Failure 500 {object} ErrorResponse "获取菜单列表失败"
 // @Security ApiKeyAuth
-// @Router /api/menus [post]
+// @Router /api/menus/page [post]
 // ListMenus 获取菜单列表
 func (c *MenuController) ListMenus(ctx *gin.Context) {
 	var req GetMenusRequest
@@ -223,18 +254,17 @@ func (c *MenuController) ListMenus(ctx *gin.Context) {
 		return
 	}
 
-	menus, total, err := c.menuService.ListMenus(req.Page, req.PageSize)
+	query := req.toQuery(map[string]interface{}{
+		"parent_id":  optionalInt(req.ParentID),
+		"is_visible": optionalBool(req.IsVisible),
+	})
+	menus, info, err := c.menuService.WithContext(ctx.Request.Context()).ListMenus(query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取菜单列表失败"})
+		respondListError(ctx, err, "获取菜单列表失败")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"data":     menus,
-		"total":    total,
-		"page":     req.Page,
-		"pageSize": req.PageSize,
-	})
+	ctx.JSON(http.StatusOK, listResponse(menus, req.ListRequest, info))
 }
 
 // GetMenusByParentID @Summary 获取子菜单列表
@@ -256,7 +286,7 @@ func (c *MenuController) GetMenusByParentID(ctx *gin.Context) {
 		return
 	}
 
-	menus, err := c.menuService.GetMenusByParentID(parentID)
+	menus, err := c.menuService.WithContext(ctx.Request.Context()).GetMenusByParentID(parentID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取子菜单列表失败"})
 		return
@@ -265,6 +295,69 @@ func (c *MenuController) GetMenusByParentID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, menus)
 }
 
+// GetMenuTree @Summary 获取菜单树
+// @Description 获取当前租户的完整菜单树，同级菜单按排序序号排列
+// @Tags 菜单管理
+// @Produce json
+// @Success 200 {array} services.MenuTreeNode "菜单树"
+// @Failure 500 {object} ErrorResponse "获取菜单树失败"
+// @Security ApiKeyAuth
+// @Router /api/menus/tree [get]
+func (c *MenuController) GetMenuTree(ctx *gin.Context) {
+	tree, err := c.menuService.WithContext(ctx.Request.Context()).GetMenuTree()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取菜单树失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tree)
+}
+
+// MoveMenu @Summary 移动菜单
+// @Description 将菜单移动到指定上级菜单下的指定位置，新旧同级菜单的排序序号自动重新编排；不能移动到自身或其子菜单下
+// @Tags 菜单管理
+// @Accept json
+// @Produce json
+// @Param id path int true "菜单ID"
+// @Param request body MoveMenuRequest true "目标位置"
+// @Success 200 {object} object "菜单移动成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数或目标上级菜单不合法"
+// @Failure 404 {object} ErrorResponse "菜单不存在"
+// @Failure 500 {object} ErrorResponse "移动菜单失败"
+// @Security ApiKeyAuth
+// @Router /api/menus/{id}/move [post]
+func (c *MenuController) MoveMenu(ctx *gin.Context) {
+	menuID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的菜单ID"})
+		return
+	}
+
+	var req MoveMenuRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+	position := -1
+	if req.Position != nil {
+		position = *req.Position
+	}
+
+	if err := c.menuService.WithContext(ctx.Request.Context()).MoveMenu(menuID, req.ParentID, position); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "菜单不存在"})
+		case errors.Is(err, services.ErrMenuParentNotFound), errors.Is(err, services.ErrMenuCycle):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "移动菜单失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "菜单移动成功"})
+}
+
 // BindPermission @Summary 为菜单绑定权限
 // @Description 为指定菜单绑定权限，需要管理员权限
 // @Tags 菜单管理
@@ -295,7 +388,11 @@ func (c *MenuController) BindPermission(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.menuService.BindMenuPermission(menuID, permission.PermissionCode, permission.Name); err != nil {
+	if err := c.menuService.WithContext(ctx.Request.Context()).BindMenuPermission(menuID, permission.PermissionCode, permission.Name); err != nil {
+		if errors.Is(err, services.ErrInvalidPermissionCode) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "绑定权限失败"})
 		return
 	}
@@ -322,7 +419,7 @@ func (c *MenuController) GetMenuPermissions(ctx *gin.Context) {
 		return
 	}
 
-	permissions, err := c.menuService.GetMenuPermissions(menuID)
+	permissions, err := c.menuService.WithContext(ctx.Request.Context()).GetMenuPermissions(menuID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取菜单权限列表失败"})
 		return
@@ -330,3 +427,50 @@ func (c *MenuController) GetMenuPermissions(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, permissions)
 }
+
+// DeleteMenu @Summary 删除菜单
+// @Description 删除指定菜单。菜单存在子菜单、按钮或权限时默认拒绝删除；传入 cascade=true 时一并删除所有子菜单、按钮、权限及权限的角色关联。删除后进入回收站，可恢复
+// @Tags 菜单管理
+// @Produce json
+// @Param id path int true "菜单ID"
+// @Param cascade query bool false "是否级联删除"
+// @Success 200 {object} object "菜单删除成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 404 {object} ErrorResponse "菜单不存在"
+// @Failure 409 {object} ErrorResponse "菜单仍被引用，不能删除"
+// @Failure 500 {object} ErrorResponse "删除菜单失败"
+// @Security ApiKeyAuth
+// @Router /api/menus/{id} [delete]
+func (c *MenuController) DeleteMenu(ctx *gin.Context) {
+	menuID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的菜单ID"})
+		return
+	}
+
+	cascade, err := strconv.ParseBool(ctx.DefaultQuery("cascade", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	if err := c.menuService.WithContext(ctx.Request.Context()).DeleteMenu(menuID, cascade); err != nil {
+		var inUse *services.MenuInUseError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "菜单不存在"})
+		case errors.As(err, &inUse):
+			ctx.JSON(http.StatusConflict, gin.H{
+				"error":       inUse.Error(),
+				"children":    inUse.Children,
+				"buttons":     inUse.Buttons,
+				"permissions": inUse.Permissions,
+			})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "删除菜单失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "菜单删除成功"})
+}
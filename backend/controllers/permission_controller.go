@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
 	"strconv"
+	"tenant-center/config"
 	"tenant-center/models"
 	"tenant-center/services"
 )
@@ -19,9 +21,9 @@ type PermissionController struct {
 }
 
 // NewPermissionController 创建权限控制器实例
-func NewPermissionController(db *gorm.DB) *PermissionController {
+func NewPermissionController(db *gorm.DB, cfg *config.Config) *PermissionController {
 	return &PermissionController{
-		permissionService: services.NewPermissionService(db),
+		permissionService: services.NewPermissionService(db, cfg),
 	}
 }
 
@@ -32,6 +34,7 @@ type CreatePermissionRequest struct {
 	Type     string `json:"type" binding:"required" example:"menu"`        // 权限类型
 	MenuID   *int   `json:"menu_id" example:"1"`                           // 菜单ID
 	ButtonID *int   `json:"button_id" example:"1"`                         // 按钮ID
+	ParentID *int   `json:"parent_id" example:"1"`                         // 父级权限ID
 }
 
 // CreatePermissionResponse 创建权限响应
@@ -47,6 +50,7 @@ type UpdatePermissionRequest struct {
 	Type     string `json:"type" example:"menu"`        // 权限类型
 	MenuID   *int   `json:"menu_id" example:"1"`        // 菜单ID
 	ButtonID *int   `json:"button_id" example:"1"`      // 按钮ID
+	ParentID *int   `json:"parent_id" example:"1"`      // 父级权限ID，为空表示顶级权限
 }
 
 // UpdatePermissionResponse 更新权限响应
@@ -58,13 +62,13 @@ type UpdatePermissionResponse struct {
 type GetPermissionsByTypeResponse []models.Permission
 
 // CreatePermission @Summary 创建权限
-// @Description 创建新权限，需要管理员权限
+// @Description 创建新权限，需要管理员权限。权限编码由冒号分隔的段组成（如 user:create），段内可用 * 通配（如 user:*、*:read），授予通配权限即授予其匹配的全部权限
 // @Tags 权限管理
 // @Accept json
 // @Produce json
 // @Param permission body CreatePermissionRequest true "权限信息"
 // @Success 201 {object} CreatePermissionResponse "权限创建成功"
-// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 400 {object} ErrorResponse "无效的请求参数、权限编码格式不合法，或父级权限、所属菜单、按钮不存在"
 // @Failure 500 {object} ErrorResponse "创建权限失败"
 // @Security ApiKeyAuth
 // @Router /api/permissions [post]
@@ -77,6 +81,7 @@ func (c *PermissionController) CreatePermission(ctx *gin.Context) {
 		Type           string `json:"type" binding:"required"`           // 保持一致的命名
 		MenuID         *int   `json:"menuId"`                            // MenuId 允许为空
 		ButtonID       *int   `json:"buttonId"`                          // ButtonId 允许为空
+		ParentID       *int   `json:"parentId"`                          // 父级权限ID，允许为空
 	}
 
 	// 绑定请求的 JSON 数据到 permission 结构体
@@ -92,10 +97,16 @@ func (c *PermissionController) CreatePermission(ctx *gin.Context) {
 		Type:     permission.Type,
 		MenuID:   permission.MenuID,
 		ButtonID: permission.ButtonID,
+		ParentID: permission.ParentID,
 	}
 
 	// 调用 service 层进行权限创建
-	if err := c.permissionService.CreatePermission(newPermission); err != nil {
+	if err := c.permissionService.WithContext(ctx.Request.Context()).CreatePermission(newPermission); err != nil {
+		if errors.Is(err, services.ErrPermissionParentNotFound) || errors.Is(err, services.ErrInvalidPermissionCode) ||
+			errors.Is(err, services.ErrMenuNotFound) || errors.Is(err, services.ErrButtonNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "创建权限失败"})
 		return
 	}
@@ -111,8 +122,11 @@ func (c *PermissionController) CreatePermission(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "权限ID"
 // @Param permission body UpdatePermissionRequest true "权限信息"
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
 // @Success 200 {object} UpdatePermissionResponse "权限信息更新成功"
-// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 400 {object} ErrorResponse "无效的请求参数、权限编码格式不合法、父级权限不合法，或所属菜单、按钮不存在"
+// @Failure 404 {object} ErrorResponse "权限不存在"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
 // @Failure 500 {object} ErrorResponse "更新权限信息失败"
 // @Security ApiKeyAuth
 // @Router /api/permissions/{id} [put]
@@ -130,6 +144,7 @@ func (c *PermissionController) UpdatePermission(ctx *gin.Context) {
 		Type     string `json:"type"`
 		MenuID   *int   `json:"menu_id"`
 		ButtonID *int   `json:"button_id"`
+		ParentID *int   `json:"parent_id"`
 	}
 
 	if err := ctx.ShouldBindJSON(&updateData); err != nil {
@@ -137,6 +152,11 @@ func (c *PermissionController) UpdatePermission(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
 	permission := &models.Permission{
 		ID:       permissionID,
 		Code:     updateData.Code,
@@ -144,28 +164,47 @@ func (c *PermissionController) UpdatePermission(ctx *gin.Context) {
 		Type:     updateData.Type,
 		MenuID:   updateData.MenuID,
 		ButtonID: updateData.ButtonID,
+		ParentID: updateData.ParentID,
+		Version:  version,
 	}
 
-	if err := c.permissionService.UpdatePermission(permission); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新权限信息失败"})
+	if err := c.permissionService.WithContext(ctx.Request.Context()).UpdatePermission(permission); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "权限不存在"})
+		case errors.Is(err, services.ErrPermissionParentNotFound), errors.Is(err, services.ErrPermissionCycle),
+			errors.Is(err, services.ErrInvalidPermissionCode), errors.Is(err, services.ErrMenuNotFound),
+			errors.Is(err, services.ErrButtonNotFound):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新权限信息失败"})
+		}
 		return
 	}
 
+	ctx.Header("ETag", versionETag(permission.Version))
 	ctx.JSON(http.StatusOK, gin.H{"message": "权限信息更新成功"})
 }
 
 // GetPermissionsRequest 获取权限列表请求参数
 type GetPermissionsRequest struct {
-	Page     int `json:"page" example:"1" binding:"required"`
-	PageSize int `json:"pageSize" example:"10" binding:"required"`
+	ListRequest
+	Type     *string `json:"type" example:"menu"`   // 按权限类型筛选：menu、button
+	MenuID   *int    `json:"menu_id" example:"1"`   // 按所属菜单筛选
+	ButtonID *int    `json:"button_id" example:"1"` // 按所属按钮筛选
+	ParentID *int    `json:"parent_id" example:"1"` // 按父级权限筛选
+	RoleID   *int    `json:"role_id" example:"1"`   // 按绑定的角色筛选
 }
 
 // GetPermissionsResponse 权限列表响应
 type GetPermissionsResponse struct {
-	Data     []models.Permission `json:"data"`
-	Total    int64               `json:"total"`
-	Page     int                 `json:"page"`
-	PageSize int                 `json:"pageSize"`
+	Data       []models.Permission `json:"data"`
+	Total      int64               `json:"total"` // 游标分页时不返回
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"pageSize"`
+	NextCursor string              `json:"next_cursor,omitempty"` // 游标分页的下一页游标，为空表示没有更多数据
 }
 
 // PagePermissions @Summary 获取权限列表
@@ -178,7 +217,7 @@ type GetPermissionsResponse struct {
 // @Failure 400 {object} ErrorResponse "无效的请求参数"
 // @Failure 500 {object} ErrorResponse "获取权限列表失败"
 // @Security ApiKeyAuth
-// @Router /api/permissions [post]
+// @Router /api/permissions/page [post]
 // PagePermissions 获取权限列表
 func (c *PermissionController) PagePermissions(ctx *gin.Context) {
 	var req GetPermissionsRequest
@@ -187,18 +226,51 @@ func (c *PermissionController) PagePermissions(ctx *gin.Context) {
 		return
 	}
 
-	permissions, total, err := c.permissionService.PagePermissions(req.Page, req.PageSize)
+	query := req.toQuery(map[string]interface{}{
+		"type":      optionalString(req.Type),
+		"menu_id":   optionalInt(req.MenuID),
+		"button_id": optionalInt(req.ButtonID),
+		"parent_id": optionalInt(req.ParentID),
+		"role_id":   optionalInt(req.RoleID),
+	})
+	permissions, info, err := c.permissionService.WithContext(ctx.Request.Context()).PagePermissions(query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取权限列表失败"})
+		respondListError(ctx, err, "获取权限列表失败")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"data":     permissions,
-		"total":    total,
-		"page":     req.Page,
-		"pageSize": req.PageSize,
-	})
+	ctx.JSON(http.StatusOK, listResponse(permissions, req.ListRequest, info))
+}
+
+// ExpandPermissionResponse 通配权限展开结果
+type ExpandPermissionResponse struct {
+	Code        string              `json:"code" example:"user:*"` // 展开的通配编码
+	Permissions []models.Permission `json:"permissions"`           // 当前匹配的具体权限
+}
+
+// ExpandPermission @Summary 展开通配权限编码
+// @Description 返回通配编码当前匹配的具体权限，按编码排序。* 只在段内匹配，不跨越冒号，如 user:* 匹配 user:create 但不匹配 user:profile:edit
+// @Tags 权限管理
+// @Produce json
+// @Param code query string true "权限编码，可包含 * 通配" example(user:*)
+// @Success 200 {object} ExpandPermissionResponse "匹配的权限"
+// @Failure 400 {object} ErrorResponse "权限编码格式不合法"
+// @Failure 500 {object} ErrorResponse "展开权限编码失败"
+// @Security ApiKeyAuth
+// @Router /api/permissions/expand [get]
+func (c *PermissionController) ExpandPermission(ctx *gin.Context) {
+	code := ctx.Query("code")
+	permissions, err := c.permissionService.WithContext(ctx.Request.Context()).ExpandPermissionCode(code)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPermissionCode) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "展开权限编码失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ExpandPermissionResponse{Code: code, Permissions: permissions})
 }
 
 // GetPermissionDetail @Summary 获取权限详情
@@ -208,11 +280,12 @@ func (c *PermissionController) PagePermissions(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "权限ID"
 // @Success 200 {object} models.Permission "权限详情"
+// @Header 200 {string} ETag "资源版本，修改时通过 If-Match 提交"
 // @Failure 400 {object} ErrorResponse "无效的权限ID"
 // @Failure 404 {object} ErrorResponse "权限不存在"
 // @Failure 500 {object} ErrorResponse "获取权限详情失败"
 // @Security ApiKeyAuth
-// @Router /api/permissions/{id} [get]
+// @Router /api/permissions/detail/{id} [get]
 func (c *PermissionController) GetPermissionDetail(ctx *gin.Context) {
 	permissionID, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
@@ -220,7 +293,7 @@ func (c *PermissionController) GetPermissionDetail(ctx *gin.Context) {
 		return
 	}
 
-	permission, err := c.permissionService.GetPermissionByID(permissionID)
+	permission, err := c.permissionService.WithContext(ctx.Request.Context()).GetPermissionByID(permissionID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "权限不存在"})
@@ -230,6 +303,7 @@ func (c *PermissionController) GetPermissionDetail(ctx *gin.Context) {
 		return
 	}
 
+	ctx.Header("ETag", versionETag(permission.Version))
 	ctx.JSON(http.StatusOK, permission)
 }
 
@@ -247,7 +321,7 @@ func (c *PermissionController) GetPermissionDetail(ctx *gin.Context) {
 func (c *PermissionController) GetPermissionsByType(ctx *gin.Context) {
 	permissionType := ctx.Param("type")
 
-	permissions, err := c.permissionService.GetPermissionsByType(permissionType)
+	permissions, err := c.permissionService.WithContext(ctx.Request.Context()).GetPermissionsByType(permissionType)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取权限列表失败"})
 		return
@@ -255,3 +329,37 @@ func (c *PermissionController) GetPermissionsByType(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, permissions)
 }
+
+// DeletePermission @Summary 删除权限
+// @Description 删除指定权限，同时解除该权限与角色的关联；存在子权限时拒绝删除。删除后进入回收站，可恢复
+// @Tags 权限管理
+// @Produce json
+// @Param id path int true "权限ID"
+// @Success 200 {object} object "权限删除成功"
+// @Failure 400 {object} ErrorResponse "无效的权限ID"
+// @Failure 404 {object} ErrorResponse "权限不存在"
+// @Failure 409 {object} ErrorResponse "权限存在子权限，不能删除"
+// @Failure 500 {object} ErrorResponse "删除权限失败"
+// @Security ApiKeyAuth
+// @Router /api/permissions/{id} [delete]
+func (c *PermissionController) DeletePermission(ctx *gin.Context) {
+	permissionID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的权限ID"})
+		return
+	}
+
+	if err := c.permissionService.WithContext(ctx.Request.Context()).DeletePermission(permissionID); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "权限不存在"})
+		case errors.Is(err, services.ErrPermissionHasChildren):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "删除权限失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "权限删除成功"})
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"tenant-center/config"
+	"tenant-center/services"
+)
+
+// @title 回收站API
+// @version 1.0
+// @description 回收站相关的API接口，用于查看、恢复和彻底删除已删除的用户、角色、权限、菜单和按钮
+
+// RecycleBinController 回收站控制器
+type RecycleBinController struct {
+	recycleBinService *services.RecycleBinService
+}
+
+// NewRecycleBinController 创建回收站控制器实例
+func NewRecycleBinController(db *gorm.DB, cfg *config.Config) *RecycleBinController {
+	return &RecycleBinController{
+		recycleBinService: services.NewRecycleBinService(db, cfg),
+	}
+}
+
+// GetRecycleBinRequest 获取回收站列表请求参数
+type GetRecycleBinRequest struct {
+	ListRequest
+	Type string `json:"type" example:"role" binding:"required"` // 资源类型：user、role、permission、menu、button
+}
+
+// GetRecycleBinResponse 回收站列表响应
+type GetRecycleBinResponse struct {
+	Data       []services.RecycleBinItem `json:"data"`
+	Total      int64                     `json:"total"` // 游标分页时不返回
+	Page       int                       `json:"page"`
+	PageSize   int                       `json:"pageSize"`
+	NextCursor string                    `json:"next_cursor,omitempty"` // 游标分页的下一页游标，为空表示没有更多数据
+}
+
+// PageDeleted @Summary 获取回收站列表
+// @Description 分页获取回收站中指定类型的记录，支持按名称搜索，默认按删除时间倒序；游标分页按删除时间排序
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Param request body GetRecycleBinRequest true "分页参数"
+// @Success 200 {object} GetRecycleBinResponse "回收站列表"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 500 {object} ErrorResponse "获取回收站列表失败"
+// @Security ApiKeyAuth
+// @Router /api/recycle-bin/page [post]
+func (c *RecycleBinController) PageDeleted(ctx *gin.Context) {
+	var req GetRecycleBinRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	items, info, err := c.recycleBinService.WithContext(ctx.Request.Context()).PageDeleted(req.Type, req.toQuery(nil))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownResourceType) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondListError(ctx, err, "获取回收站列表失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listResponse(items, req.ListRequest, info))
+}
+
+// Restore @Summary 恢复记录
+// @Description 从回收站恢复记录，同一次删除中一并删除的子菜单、按钮、权限随之恢复，删除时解除的用户角色、角色权限关联也会重新建立
+// @Tags 回收站
+// @Produce json
+// @Param type path string true "资源类型：user、role、permission、menu、button"
+// @Param id path int true "记录ID"
+// @Success 200 {object} object "恢复成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 404 {object} ErrorResponse "回收站中不存在该记录"
+// @Failure 409 {object} ErrorResponse "上级记录仍在回收站中，或已存在相同用户名或编码的记录"
+// @Failure 500 {object} ErrorResponse "恢复失败"
+// @Security ApiKeyAuth
+// @Router /api/recycle-bin/{type}/{id}/restore [post]
+func (c *RecycleBinController) Restore(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的记录ID"})
+		return
+	}
+
+	if err := c.recycleBinService.WithContext(ctx.Request.Context()).Restore(ctx.Param("type"), id); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownResourceType):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "回收站中不存在该记录"})
+		case errors.Is(err, services.ErrParentNotRestored), errors.Is(err, services.ErrRestoreDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "恢复失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "恢复成功"})
+}
+
+// Purge @Summary 彻底删除记录
+// @Description 彻底删除回收站中的记录，同一次删除中一并删除的记录随之彻底删除，操作不可恢复
+// @Tags 回收站
+// @Produce json
+// @Param type path string true "资源类型：user、role、permission、menu、button"
+// @Param id path int true "记录ID"
+// @Success 200 {object} object "彻底删除成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 404 {object} ErrorResponse "回收站中不存在该记录"
+// @Failure 500 {object} ErrorResponse "彻底删除失败"
+// @Security ApiKeyAuth
+// @Router /api/recycle-bin/{type}/{id} [delete]
+func (c *RecycleBinController) Purge(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的记录ID"})
+		return
+	}
+
+	if err := c.recycleBinService.WithContext(ctx.Request.Context()).Purge(ctx.Param("type"), id); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownResourceType):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "回收站中不存在该记录"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "彻底删除失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "彻底删除成功"})
+}
@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
 	"strconv"
+	"tenant-center/config"
 	"tenant-center/models"
 	"tenant-center/services"
 )
@@ -19,67 +23,23 @@ type RoleController struct {
 }
 
 // NewRoleController 创建角色控制器实例
-func NewRoleController(db *gorm.DB) *RoleController {
+func NewRoleController(db *gorm.DB, cfg *config.Config) *RoleController {
 	return &RoleController{
-		roleService: services.NewRoleService(db),
+		roleService: services.NewRoleService(db, cfg),
 	}
 }
 
-// CreateRole @Summary 创建角色
-// @Description 创建新角色，需要管理员权限
-// @Tags 角色管理
-// @Accept json
-// @Produce json
-// @Param role body CreateRoleRequest true "角色信息"
-// @Success 201 {object} CreateRoleResponse "角色创建成功"
-// @Failure 400 {object} ErrorResponse "无效的请求参数"
-// @Failure 500 {object} ErrorResponse "创建角色失败"
-// @Security ApiKeyAuth
-// @Router /api/roles [post]
-
-// UpdateRole @Summary 更新角色信息
-// @Description 更新指定角色的信息，需要管理员权限
-// @Tags 角色管理
-// @Accept json
-// @Produce json
-// @Param id path int true "角色ID"
-// @Param role body UpdateRoleRequest true "角色信息"
-// @Success 200 {object} UpdateRoleResponse "角色信息更新成功"
-// @Failure 400 {object} ErrorResponse "无效的请求参数"
-// @Failure 500 {object} ErrorResponse "更新角色信息失败"
-// @Security ApiKeyAuth
-// @Router /api/roles/{id} [put]
-
-// PageRoles @Summary 获取角色列表
-// @Description 获取所有角色的列表，需要管理员权限
-// @Tags 角色管理
-// @Accept json
-// @Produce json
-// @Success 200 {array} ListRolesResponse "角色列表"
-// @Failure 500 {object} ErrorResponse "获取角色列表失败"
-// @Security ApiKeyAuth
-// @Router /api/roles [get]
+// CreateRoleRequest 创建角色请求参数
+type CreateRoleRequest struct {
+	Name        string `json:"name" binding:"required" example:"管理员"`         // 角色名称
+	Code        string `json:"code" binding:"required" example:"ROLE_EDITOR"` // 角色编码，租户内唯一
+	Description string `json:"description" example:"内容编辑角色"`                  // 角色描述
+}
 
-// BindPermissions @Summary 为角色绑定权限
-// @Description 为指定角色绑定一个或多个权限，需要管理员权限
-// @Tags 角色管理
-// @Accept json
-// @Produce json
-// @Param id path int true "角色ID"
-// @Param permissionIDs body BindPermissionsRequest true "权限ID列表" example:[1,2,3]
-// @Success 200 {object} BindPermissionsResponse "权限绑定成功"
-// @Failure 400 {object} ErrorResponse "无效的请求参数"
-// @Failure 500 {object} ErrorResponse "绑定权限失败"
-// @Security ApiKeyAuth
-// @Router /api/roles/{id}/permissions [post]
-
-// PermissionTreeNode 权限树节点
-type PermissionTreeNode struct {
-	ID       int                  `json:"id"`
-	Name     string               `json:"name"`
-	Enable   bool                 `json:"enable"`
-	Icon     string               `json:"icon,omitempty"`
-	Children []PermissionTreeNode `json:"children"`
+// CreateRoleResponse 创建角色响应
+type CreateRoleResponse struct {
+	Message string `json:"message" example:"角色创建成功"` // 响应消息
+	ID      int    `json:"id" example:"1"`           // 角色ID
 }
 
 // GetDetail @Summary 获取角色详情
@@ -89,6 +49,7 @@ type PermissionTreeNode struct {
 // @Produce json
 // @Param id path int true "角色ID"
 // @Success 200 {object} models.Role "角色详情"
+// @Header 200 {string} ETag "资源版本，修改时通过 If-Match 提交"
 // @Failure 400 {object} ErrorResponse "无效的角色ID"
 // @Failure 500 {object} ErrorResponse "获取角色详情失败"
 // @Security ApiKeyAuth
@@ -100,23 +61,25 @@ func (c *RoleController) GetDetail(ctx *gin.Context) {
 		return
 	}
 
-	role, err := c.roleService.GetRoleByID(roleID)
+	role, err := c.roleService.WithContext(ctx.Request.Context()).GetRoleByID(roleID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取角色详情失败"})
 		return
 	}
 
+	ctx.Header("ETag", versionETag(role.Version))
 	ctx.JSON(http.StatusOK, role)
 }
 
 // GetRolePermissions @Summary 获取角色的权限列表
-// @Description 获取指定角色的所有权限列表，按层级结构返回
+// @Description 获取完整的权限树并标记角色拥有的权限，按层级结构返回。enable 按配置的授予模式计算（授予父权限是否包含子孙权限、授予子权限是否包含上级权限），包括从父角色继承的权限。state 为 allowed、denied 或 unset，拒绝优先于授予，enable 即 state 为 allowed；own 表示当前状态来自角色自身的绑定，inherited_from 列出决定当前状态的祖先角色ID；half_checked 表示子孙权限只授予了一部分
 // @Tags 角色管理
 // @Accept json
 // @Produce json
 // @Param id path int true "角色ID"
-// @Success 200 {array} PermissionTreeNode "权限树形列表"
+// @Success 200 {array} services.PermissionTreeNode "权限树形列表"
 // @Failure 400 {object} ErrorResponse "无效的角色ID"
+// @Failure 404 {object} ErrorResponse "角色不存在"
 // @Failure 500 {object} ErrorResponse "获取角色权限列表失败"
 // @Security ApiKeyAuth
 // @Router /api/roles/{id}/permissions [get]
@@ -127,72 +90,30 @@ func (c *RoleController) GetRolePermissions(ctx *gin.Context) {
 		return
 	}
 
-	// 获取所有权限
-	allPermissions, err := c.roleService.GetAllPermissions()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取所有权限列表失败"})
-		return
-	}
-
-	// 获取角色已分配的权限
-	rolePermissions, err := c.roleService.GetRolePermissions(roleID)
+	permissionTree, err := c.roleService.WithContext(ctx.Request.Context()).GetRolePermissionTree(roleID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "角色不存在"})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取角色权限列表失败"})
 		return
 	}
 
-	// 创建角色权限ID集合，用于快速查找
-	rolePermMap := make(map[int]bool)
-	for _, perm := range rolePermissions {
-		rolePermMap[perm.ID] = true
-	}
-
-	// 构建权限树
-	permissionMap := make(map[int][]models.Permission)
-	rootPermissions := make([]models.Permission, 0)
-
-	// 按父ID对所有权限进行分组
-	for _, perm := range allPermissions {
-		if perm.ParentID == nil || *perm.ParentID == 0 {
-			rootPermissions = append(rootPermissions, perm)
-		} else {
-			permissionMap[*perm.ParentID] = append(permissionMap[*perm.ParentID], perm)
-		}
-	}
-
-	// 递归构建权限树
-	buildPermissionTree := func(perms []models.Permission) []PermissionTreeNode {
-		var buildTree func(permission models.Permission) PermissionTreeNode
-		buildTree = func(permission models.Permission) PermissionTreeNode {
-			node := PermissionTreeNode{
-				ID:       permission.ID,
-				Name:     permission.Name,
-				Enable:   rolePermMap[permission.ID], // 根据角色权限设置启用状态
-				Children: make([]PermissionTreeNode, 0),
-			}
-
-			if children, ok := permissionMap[permission.ID]; ok {
-				for _, child := range children {
-					node.Children = append(node.Children, buildTree(child))
-				}
-			}
-
-			return node
-		}
-
-		treeNodes := make([]PermissionTreeNode, 0)
-		for _, perm := range perms {
-			treeNodes = append(treeNodes, buildTree(perm))
-		}
-		return treeNodes
-	}
-
-	// 生成最终的权限树
-	permissionTree := buildPermissionTree(rootPermissions)
-
 	ctx.JSON(http.StatusOK, permissionTree)
 }
 
+// CreateRole @Summary 创建角色
+// @Description 创建新角色，需要管理员权限
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Param role body CreateRoleRequest true "角色信息"
+// @Success 201 {object} CreateRoleResponse "角色创建成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数，或使用了保留的超级管理员角色编码"
+// @Failure 500 {object} ErrorResponse "创建角色失败"
+// @Security ApiKeyAuth
+// @Router /api/roles [post]
 // CreateRole 创建角色
 func (c *RoleController) CreateRole(ctx *gin.Context) {
 	var role struct {
@@ -212,7 +133,11 @@ func (c *RoleController) CreateRole(ctx *gin.Context) {
 		Description: role.Description,
 	}
 
-	if err := c.roleService.CreateRole(newRole); err != nil {
+	if err := c.roleService.WithContext(ctx.Request.Context()).CreateRole(newRole); err != nil {
+		if errors.Is(err, services.ErrReservedRoleCode) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "创建角色失败"})
 		return
 	}
@@ -227,8 +152,11 @@ func (c *RoleController) CreateRole(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "角色ID"
 // @Param role body object true "角色信息"
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
 // @Success 200 {object} object "角色信息更新成功"
-// @Failure 400 {object} object "无效的请求参数"
+// @Failure 400 {object} object "无效的请求参数，或将编码改为保留的超级管理员角色编码"
+// @Failure 404 {object} ErrorResponse "角色不存在"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
 // @Failure 500 {object} object "更新角色信息失败"
 // @Security ApiKeyAuth
 // @Router /api/roles/{id} [put]
@@ -251,33 +179,52 @@ func (c *RoleController) UpdateRole(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
 	role := &models.Role{
 		ID:          roleID,
 		Name:        updateData.Name,
 		Code:        updateData.Code,
 		Description: updateData.Description,
+		Version:     version,
 	}
 
-	if err := c.roleService.UpdateRole(role); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新角色信息失败"})
+	if err := c.roleService.WithContext(ctx.Request.Context()).UpdateRole(role); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "角色不存在"})
+		case errors.Is(err, services.ErrReservedRoleCode):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新角色信息失败"})
+		}
 		return
 	}
 
+	ctx.Header("ETag", versionETag(role.Version))
 	ctx.JSON(http.StatusOK, gin.H{"message": "角色信息更新成功"})
 }
 
 // GetRolesRequest 获取角色列表请求参数
 type GetRolesRequest struct {
-	Page     int `json:"page" example:"1" binding:"required"`
-	PageSize int `json:"pageSize" example:"10" binding:"required"`
+	ListRequest
+	Code         *string `json:"code" example:"ROLE_ADMIN"` // 按角色编码精确筛选
+	PermissionID *int    `json:"permission_id" example:"1"` // 按拥有的权限筛选
+	UserID       *int    `json:"user_id" example:"1"`       // 按绑定的用户筛选
 }
 
 // GetRolesResponse 角色列表响应
 type GetRolesResponse struct {
-	Data     []models.Role `json:"data"`
-	Total    int64         `json:"total"`
-	Page     int           `json:"page"`
-	PageSize int           `json:"pageSize"`
+	Data       []models.Role `json:"data"`
+	Total      int64         `json:"total"` // 游标分页时不返回
+	Page       int           `json:"page"`
+	PageSize   int           `json:"pageSize"`
+	NextCursor string        `json:"next_cursor,omitempty"` // 游标分页的下一页游标，为空表示没有更多数据
 }
 
 // PageRoles @Summary 获取角色列表
@@ -290,7 +237,7 @@ type GetRolesResponse struct {
 // @Failure 400 {object} ErrorResponse "无效的请求参数"
 // @Failure 500 {object} ErrorResponse "获取角色列表失败"
 // @Security ApiKeyAuth
-// @Router /api/roles [post]
+// @Router /api/roles/page [post]
 // PageRoles 获取角色列表
 func (c *RoleController) PageRoles(ctx *gin.Context) {
 	var req GetRolesRequest
@@ -299,33 +246,68 @@ func (c *RoleController) PageRoles(ctx *gin.Context) {
 		return
 	}
 
-	roles, total, err := c.roleService.PageRoles(req.Page, req.PageSize)
+	query := req.toQuery(map[string]interface{}{
+		"code":          optionalString(req.Code),
+		"permission_id": optionalInt(req.PermissionID),
+		"user_id":       optionalInt(req.UserID),
+	})
+	roles, info, err := c.roleService.WithContext(ctx.Request.Context()).PageRoles(query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取角色列表失败"})
+		respondListError(ctx, err, "获取角色列表失败")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"data":     roles,
-		"total":    total,
-		"page":     req.Page,
-		"pageSize": req.PageSize,
-	})
+	ctx.JSON(http.StatusOK, listResponse(roles, req.ListRequest, info))
+}
+
+// PermissionRefs 权限编码或权限ID列表，ID 可以写成数字或字符串，如 ["user:create", 5, "6"]
+type PermissionRefs []string
+
+// UnmarshalJSON 将数字形式的权限ID统一转换为字符串
+func (r *PermissionRefs) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	refs := make(PermissionRefs, 0, len(items))
+	for _, item := range items {
+		var code string
+		if err := json.Unmarshal(item, &code); err == nil {
+			refs = append(refs, code)
+			continue
+		}
+		var id int
+		if err := json.Unmarshal(item, &id); err != nil {
+			return fmt.Errorf("权限应为编码或ID: %s", item)
+		}
+		refs = append(refs, strconv.Itoa(id))
+	}
+	*r = refs
+	return nil
+}
+
+// BindPermissionsRequest 替换角色权限请求参数
+type BindPermissionsRequest struct {
+	Permissions PermissionRefs `json:"permissions" binding:"required" swaggertype:"array,string" example:"user:create,5"` // 授予的权限编码或ID
+	Deny        PermissionRefs `json:"deny" swaggertype:"array,string" example:"user:delete"`                             // 显式拒绝的权限编码或ID
+	Strict      bool           `json:"strict" example:"false"`                                                            // 为 true 时存在找不到的权限即整体失败，不做修改
 }
 
 // BindPermissions @Summary 为角色绑定权限
-// @Description 为指定角色绑定一个或多个权限，需要管理员权限
+// @Description 用给定的权限替换角色当前绑定的全部权限，需要管理员权限。每一项可以是权限编码或权限ID，找不到的条目在 unknown 中返回并被忽略，strict 为 true 时整体失败并返回 400。deny 中的权限为显式拒绝，用户的任一角色拒绝某权限时，其他角色的授予不再生效
 // @Tags 角色管理
 // @Accept json
 // @Produce json
 // @Param id path int true "角色ID"
-// @Param permissions body BindPermissionsRequest true "权限编码列表"
-// @Success 200 {object} object "权限绑定成功"
-// @Failure 400 {object} object "无效的请求参数"
-// @Failure 500 {object} object "绑定权限失败"
+// @Param request body BindPermissionsRequest true "授予和拒绝的权限编码或ID"
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
+// @Success 200 {object} services.BindPermissionsResult "替换后的权限绑定及找不到的条目"
+// @Failure 400 {object} ErrorResponse "无效的请求参数、同一权限同时出现在授予和拒绝中，或严格模式下存在找不到的权限"
+// @Failure 404 {object} ErrorResponse "角色不存在"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
+// @Failure 500 {object} ErrorResponse "绑定权限失败"
 // @Security ApiKeyAuth
-// @Router /api/roles/{id}/permissions [post]
-// BindPermissions 为角色绑定权限
+// @Router /api/roles/{id}/bindPermissions [post]
 func (c *RoleController) BindPermissions(ctx *gin.Context) {
 	roleID, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
@@ -333,18 +315,220 @@ func (c *RoleController) BindPermissions(ctx *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Permissions []string `json:"permissions" binding:"required"`
+	var req BindPermissionsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
 	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.roleService.WithContext(ctx.Request.Context()).BindRolePermissionsByCode(roleID, version, req.Permissions, req.Deny, req.Strict)
+	if err != nil {
+		var unknown *services.UnknownPermissionsError
+		switch {
+		case errors.As(err, &unknown):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "unknown": unknown.Entries})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "角色不存在"})
+		case errors.Is(err, services.ErrPermissionAllowDenyConflict):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "绑定权限失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"ok":          true,
+		"message":     "权限绑定成功",
+		"permissions": result.Permissions,
+		"unknown":     result.Unknown,
+	})
+}
+
+// PatchPermissionsRequest 增量修改角色权限请求参数
+type PatchPermissionsRequest struct {
+	Allow  []int `json:"allow" example:"1,2"` // 授予的权限ID，已拒绝的改为授予
+	Deny   []int `json:"deny" example:"3"`    // 拒绝的权限ID，已授予的改为拒绝
+	Remove []int `json:"remove" example:"4"`  // 解除绑定的权限ID，未绑定的忽略
+}
+
+// PatchPermissionsResponse 增量修改角色权限响应
+type PatchPermissionsResponse struct {
+	Permissions []services.RolePermissionBinding `json:"permissions"` // 修改后角色的全部权限绑定
+}
+
+// PatchPermissions @Summary 增量修改角色权限
+// @Description 为角色授予、拒绝或解除指定权限，不影响其他已绑定的权限，重复提交结果相同。返回修改后角色的全部权限绑定。需要一次性替换全部权限时使用 bindPermissions 接口
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Param id path int true "角色ID"
+// @Param request body PatchPermissionsRequest true "授予、拒绝和解除的权限ID"
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
+// @Success 200 {object} PatchPermissionsResponse "修改后的权限绑定"
+// @Failure 400 {object} ErrorResponse "无效的请求参数、权限不存在或同一权限出现在多个列表中"
+// @Failure 404 {object} ErrorResponse "角色不存在"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
+// @Failure 500 {object} ErrorResponse "修改权限失败"
+// @Security ApiKeyAuth
+// @Router /api/roles/{id}/permissions [patch]
+func (c *RoleController) PatchPermissions(ctx *gin.Context) {
+	roleID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的角色ID"})
+		return
+	}
+
+	var req PatchPermissionsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	bindings, err := c.roleService.WithContext(ctx.Request.Context()).PatchRolePermissions(roleID, version, req.Allow, req.Deny, req.Remove)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "角色不存在"})
+		case errors.Is(err, services.ErrPermissionNotFound),
+			errors.Is(err, services.ErrPermissionAllowDenyConflict),
+			errors.Is(err, services.ErrAddRemoveConflict):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "修改权限失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PatchPermissionsResponse{Permissions: bindings})
+}
+
+// BindParentsRequest 设置父角色请求参数
+type BindParentsRequest struct {
+	ParentIDs []int `json:"parent_ids"` // 父角色ID列表，传空列表表示不再继承任何角色
+}
+
+// BindParents @Summary 设置角色继承的父角色
+// @Description 用给定的父角色列表替换角色当前的继承关系，角色拥有父角色及其全部祖先角色的权限。可以继承多个父角色，继承关系不能形成环
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Param id path int true "角色ID"
+// @Param request body BindParentsRequest true "父角色ID列表"
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
+// @Success 200 {object} object "父角色设置成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数、父角色不存在或继承关系形成环"
+// @Failure 404 {object} ErrorResponse "角色不存在"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
+// @Failure 500 {object} ErrorResponse "设置父角色失败"
+// @Security ApiKeyAuth
+// @Router /api/roles/{id}/parents [post]
+func (c *RoleController) BindParents(ctx *gin.Context) {
+	roleID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的角色ID"})
+		return
+	}
+
+	var req BindParentsRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
 		return
 	}
 
-	if err := c.roleService.BindRolePermissionsByCode(roleID, req.Permissions); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "绑定权限失败"})
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.roleService.WithContext(ctx.Request.Context()).BindRoleParents(roleID, version, req.ParentIDs); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "角色不存在"})
+		case errors.Is(err, services.ErrParentRoleNotFound), errors.Is(err, services.ErrRoleCycle):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "设置父角色失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "父角色设置成功"})
+}
+
+// GetEffectivePermissions @Summary 获取角色生效的权限
+// @Description 列出角色授予和拒绝的全部权限（按授予模式展开），state 为 allowed 或 denied，own 表示当前状态来自角色自身的绑定，inherited_from 列出决定当前状态的祖先角色ID；同时返回直接父角色和全部祖先角色
+// @Tags 角色管理
+// @Produce json
+// @Param id path int true "角色ID"
+// @Success 200 {object} services.RoleEffectivePermissions "角色生效的权限"
+// @Failure 400 {object} ErrorResponse "无效的角色ID"
+// @Failure 404 {object} ErrorResponse "角色不存在"
+// @Failure 500 {object} ErrorResponse "获取角色权限失败"
+// @Security ApiKeyAuth
+// @Router /api/roles/{id}/effective-permissions [get]
+func (c *RoleController) GetEffectivePermissions(ctx *gin.Context) {
+	roleID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的角色ID"})
+		return
+	}
+
+	result, err := c.roleService.WithContext(ctx.Request.Context()).GetRoleEffectivePermissions(roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "角色不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取角色权限失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteRole @Summary 删除角色
+// @Description 删除指定角色，同时解除该角色与用户、权限的关联以及与其他角色的继承关系。删除后进入回收站，可恢复
+// @Tags 角色管理
+// @Produce json
+// @Param id path int true "角色ID"
+// @Success 200 {object} object "角色删除成功"
+// @Failure 400 {object} ErrorResponse "无效的角色ID"
+// @Failure 404 {object} ErrorResponse "角色不存在"
+// @Failure 500 {object} ErrorResponse "删除角色失败"
+// @Security ApiKeyAuth
+// @Router /api/roles/{id} [delete]
+func (c *RoleController) DeleteRole(ctx *gin.Context) {
+	roleID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的角色ID"})
+		return
+	}
+
+	if err := c.roleService.WithContext(ctx.Request.Context()).DeleteRole(roleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "角色不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "删除角色失败"})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"ok": true, "message": "权限绑定成功"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "角色删除成功"})
 }
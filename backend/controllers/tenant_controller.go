@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"tenant-center/config"
+	"tenant-center/models"
+	"tenant-center/services"
+)
+
+// @title 租户管理API
+// @version 1.0
+// @description 租户管理相关的API接口，包括创建租户、更新租户、获取租户详情和租户列表等功能，仅平台租户可访问
+
+// TenantController 租户控制器
+type TenantController struct {
+	tenantService *services.TenantService
+}
+
+// NewTenantController 创建租户控制器实例
+func NewTenantController(db *gorm.DB, cfg *config.Config) *TenantController {
+	return &TenantController{
+		tenantService: services.NewTenantService(db, cfg),
+	}
+}
+
+// CreateTenantRequest 创建租户请求参数
+type CreateTenantRequest struct {
+	Code          string `json:"code" binding:"required" example:"acme"`    // 租户编码
+	Name          string `json:"name" binding:"required" example:"ACME 公司"` // 租户名称
+	AdminUsername string `json:"admin_username" example:"acme_admin"`       // 初始管理员用户名，可选
+	AdminPassword string `json:"admin_password" example:"123456"`           // 初始管理员密码，填写管理员用户名时必填
+}
+
+// CreateTenantResponse 创建租户响应
+type CreateTenantResponse struct {
+	Message string `json:"message" example:"租户创建成功"` // 响应消息
+	ID      int    `json:"id" example:"2"`           // 租户ID
+}
+
+// UpdateTenantRequest 更新租户请求参数
+type UpdateTenantRequest struct {
+	Name   string `json:"name" example:"ACME 公司"`  // 租户名称
+	Status string `json:"status" example:"active"` // 租户状态：active、disabled
+}
+
+// GetTenantsRequest 获取租户列表请求参数
+type GetTenantsRequest struct {
+	ListRequest
+	Status *string `json:"status" example:"active"` // 按租户状态筛选
+}
+
+// GetTenantsResponse 租户列表响应
+type GetTenantsResponse struct {
+	Data       []models.Tenant `json:"data"`
+	Total      int64           `json:"total"` // 游标分页时不返回
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	NextCursor string          `json:"next_cursor,omitempty"` // 游标分页的下一页游标，为空表示没有更多数据
+}
+
+// CreateTenant @Summary 创建租户
+// @Description 创建新租户，可同时创建租户的初始管理员账号，仅平台租户可访问
+// @Tags 租户管理
+// @Accept json
+// @Produce json
+// @Param tenant body CreateTenantRequest true "租户信息"
+// @Success 201 {object} CreateTenantResponse "租户创建成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 500 {object} ErrorResponse "创建租户失败"
+// @Security ApiKeyAuth
+// @Router /api/tenants [post]
+// CreateTenant 创建租户
+func (c *TenantController) CreateTenant(ctx *gin.Context) {
+	var req CreateTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	if req.AdminUsername != "" && req.AdminPassword == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "请填写管理员密码"})
+		return
+	}
+
+	newTenant := &models.Tenant{
+		Code:   req.Code,
+		Name:   req.Name,
+		Status: models.TenantStatusActive,
+	}
+
+	if err := c.tenantService.WithContext(ctx.Request.Context()).CreateTenant(newTenant, req.AdminUsername, req.AdminPassword); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "创建租户失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "租户创建成功", "id": newTenant.ID})
+}
+
+// UpdateTenant @Summary 更新租户信息
+// @Description 更新指定租户的名称和状态，停用后该租户的用户无法登录，仅平台租户可访问
+// @Tags 租户管理
+// @Accept json
+// @Produce json
+// @Param id path int true "租户ID"
+// @Param tenant body UpdateTenantRequest true "租户信息"
+// @Success 200 {object} object "租户信息更新成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 500 {object} ErrorResponse "更新租户信息失败"
+// @Security ApiKeyAuth
+// @Router /api/tenants/{id} [put]
+// UpdateTenant 更新租户信息
+func (c *TenantController) UpdateTenant(ctx *gin.Context) {
+	tenantID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的租户ID"})
+		return
+	}
+
+	var req UpdateTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	if req.Status != models.TenantStatusActive && req.Status != models.TenantStatusDisabled {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的租户状态"})
+		return
+	}
+
+	tenant := &models.Tenant{
+		ID:     tenantID,
+		Name:   req.Name,
+		Status: req.Status,
+	}
+
+	if err := c.tenantService.WithContext(ctx.Request.Context()).UpdateTenant(tenant); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新租户信息失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "租户信息更新成功"})
+}
+
+// GetDetail @Summary 获取租户详情
+// @Description 获取指定租户的详细信息，仅平台租户可访问
+// @Tags 租户管理
+// @Accept json
+// @Produce json
+// @Param id path int true "租户ID"
+// @Success 200 {object} models.Tenant "租户详情"
+// @Failure 400 {object} ErrorResponse "无效的租户ID"
+// @Failure 404 {object} ErrorResponse "租户不存在"
+// @Failure 500 {object} ErrorResponse "获取租户详情失败"
+// @Security ApiKeyAuth
+// @Router /api/tenants/detail/{id} [get]
+func (c *TenantController) GetDetail(ctx *gin.Context) {
+	tenantID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的租户ID"})
+		return
+	}
+
+	tenant, err := c.tenantService.WithContext(ctx.Request.Context()).GetTenantByID(tenantID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "租户不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取租户详情失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tenant)
+}
+
+// PageTenants @Summary 获取租户列表
+// @Description 获取所有租户的列表，支持分页，仅平台租户可访问
+// @Tags 租户管理
+// @Accept json
+// @Produce json
+// @Param request body GetTenantsRequest true "分页参数"
+// @Success 200 {object} GetTenantsResponse "租户列表"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 500 {object} ErrorResponse "获取租户列表失败"
+// @Security ApiKeyAuth
+// @Router /api/tenants/page [post]
+// PageTenants 获取租户列表
+func (c *TenantController) PageTenants(ctx *gin.Context) {
+	var req GetTenantsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	query := req.toQuery(map[string]interface{}{
+		"status": optionalString(req.Status),
+	})
+	tenants, info, err := c.tenantService.WithContext(ctx.Request.Context()).PageTenants(query)
+	if err != nil {
+		respondListError(ctx, err, "获取租户列表失败")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, listResponse(tenants, req.ListRequest, info))
+}
@@ -1,11 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
 	"strconv"
+	"tenant-center/config"
 	"tenant-center/models"
 	"tenant-center/services"
 )
@@ -16,27 +18,18 @@ import (
 
 // UserController 用户控制器
 type UserController struct {
-	userService *services.UserService
+	userService  *services.UserService
+	tokenService *services.TokenService
 }
 
 // NewUserController 创建用户控制器实例
-func NewUserController(db *gorm.DB) *UserController {
+func NewUserController(db *gorm.DB, cfg *config.Config, tokenService *services.TokenService) *UserController {
 	return &UserController{
-		userService: services.NewUserService(db),
+		userService:  services.NewUserService(db, cfg),
+		tokenService: tokenService,
 	}
 }
 
-// Login @Summary 用户登录
-// @Description 用户登录接口，验证用户名和密码，返回JWT token
-// @Tags 用户管理
-// @Accept json
-// @Produce json
-// @Param loginData body LoginRequest true "登录信息"
-// @Success 200 {object} LoginResponse "登录成功，返回token"
-// @Failure 400 {object} ErrorResponse "无效的请求参数"
-// @Failure 401 {object} ErrorResponse "用户名或密码错误"
-// @Router /api/login [post]
-
 // LoginRequest 登录请求参数
 type LoginRequest struct {
 	Username string `json:"username" binding:"required" example:"admin"`  // 用户名
@@ -45,7 +38,45 @@ type LoginRequest struct {
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // JWT令牌
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // JWT访问令牌
+	RefreshToken string `json:"refresh_token" example:"q1Xv0n..."`                       // 刷新令牌，每次刷新后轮换
+	ExpiresIn    int64  `json:"expires_in" example:"900"`                                // 访问令牌有效期，单位秒
+	TokenType    string `json:"token_type" example:"Bearer"`                             // 令牌类型
+}
+
+// RefreshTokenRequest 刷新令牌请求参数
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"q1Xv0n..."` // 刷新令牌
+}
+
+// LogoutRequest 退出登录请求参数
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" example:"q1Xv0n..."` // 刷新令牌，传入时一并吊销其所在的令牌族
+}
+
+// CreateUserRequest 创建用户请求参数
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required" example:"editor"` // 用户名，未删除的用户中全局唯一
+	Password string `json:"password" binding:"required" example:"123456"` // 密码
+	Email    string `json:"email" example:"editor@example.com"`           // 邮箱
+	Phone    string `json:"phone" example:"13800000000"`                  // 手机号
+}
+
+// CreateUserResponse 创建用户响应
+type CreateUserResponse struct {
+	Message string `json:"message" example:"用户创建成功"` // 响应消息
+	ID      int    `json:"id" example:"1"`           // 用户ID
+}
+
+// UpdateUserRequest 更新用户请求参数
+type UpdateUserRequest struct {
+	Username string `json:"username" example:"editor"` // 用户名
+	Password string `json:"password" example:"654321"` // 新密码
+}
+
+// UpdateUserResponse 更新用户响应
+type UpdateUserResponse struct {
+	Message string `json:"message" example:"用户信息更新成功"` // 响应消息
 }
 
 // ErrorResponse 错误响应
@@ -53,6 +84,16 @@ type ErrorResponse struct {
 	Error string `json:"error" example:"无效的请求参数"` // 错误信息
 }
 
+// Login @Summary 用户登录
+// @Description 用户登录接口，验证用户名和密码，返回访问令牌和刷新令牌
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param loginData body LoginRequest true "登录信息"
+// @Success 200 {object} LoginResponse "登录成功，返回token"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 401 {object} ErrorResponse "用户名或密码错误"
+// @Router /api/login [post]
 // Login 用户登录
 func (c *UserController) Login(ctx *gin.Context) {
 	var loginData struct {
@@ -65,40 +106,87 @@ func (c *UserController) Login(ctx *gin.Context) {
 		return
 	}
 
-	token, err := c.userService.Login(loginData.Username, loginData.Password)
+	user, err := c.userService.WithContext(ctx.Request.Context()).Authenticate(loginData.Username, loginData.Password)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"token": token})
+	tokens, err := c.tokenService.WithContext(ctx.Request.Context()).IssueTokens(user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "签发token失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
 }
 
-// CreateUser @Summary 创建用户
-// @Description 创建新用户，需要管理员权限
+// RefreshToken @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌立即失效；已使用过的刷新令牌再次提交时整个登录会话将被吊销
 // @Tags 用户管理
 // @Accept json
 // @Produce json
-// @Param user body CreateUserRequest true "用户信息"
-// @Success 201 {object} CreateUserResponse "用户创建成功"
+// @Param request body RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} LoginResponse "刷新成功，返回新的令牌"
 // @Failure 400 {object} ErrorResponse "无效的请求参数"
-// @Failure 500 {object} ErrorResponse "创建用户失败"
+// @Failure 401 {object} ErrorResponse "刷新令牌无效或已被使用"
+// @Router /api/token/refresh [post]
+func (c *UserController) RefreshToken(ctx *gin.Context) {
+	var req RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	tokens, err := c.tokenService.WithContext(ctx.Request.Context()).Refresh(req.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// Logout @Summary 退出登录
+// @Description 吊销当前访问令牌，传入刷新令牌时一并吊销该登录会话的全部刷新令牌
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param request body LogoutRequest false "刷新令牌"
+// @Success 200 {object} object "退出成功"
+// @Failure 500 {object} ErrorResponse "退出登录失败"
 // @Security ApiKeyAuth
-// @Router /api/users [post]
+// @Router /api/logout [post]
+func (c *UserController) Logout(ctx *gin.Context) {
+	var req LogoutRequest
+	// 请求体可选，解析失败时只吊销访问令牌
+	_ = ctx.ShouldBindJSON(&req)
+
+	claims, ok := ctx.MustGet("claims").(*services.AccessClaims)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "无效的token"})
+		return
+	}
 
-// UpdateUser @Summary 更新用户信息
-// @Description 更新指定用户的信息，需要管理员权限
+	if err := c.tokenService.WithContext(ctx.Request.Context()).Logout(claims, req.RefreshToken); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "退出登录失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "退出成功"})
+}
+
+// CreateUser @Summary 创建用户
+// @Description 创建新用户，需要管理员权限
 // @Tags 用户管理
 // @Accept json
 // @Produce json
-// @Param id path int true "用户ID"
-// @Param user body UpdateUserRequest true "用户信息"
-// @Success 200 {object} UpdateUserResponse "用户信息更新成功"
+// @Param user body CreateUserRequest true "用户信息"
+// @Success 201 {object} CreateUserResponse "用户创建成功"
 // @Failure 400 {object} ErrorResponse "无效的请求参数"
-// @Failure 500 {object} ErrorResponse "更新用户信息失败"
+// @Failure 500 {object} ErrorResponse "创建用户失败"
 // @Security ApiKeyAuth
-// @Router /api/users/{id} [put]
-
+// @Router /api/users [post]
 // CreateUser 创建用户
 func (c *UserController) CreateUser(ctx *gin.Context) {
 	var user struct {
@@ -118,7 +206,7 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 		Password: user.Password,
 	}
 
-	if err := c.userService.CreateUser(newUser); err != nil {
+	if err := c.userService.WithContext(ctx.Request.Context()).CreateUser(newUser); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "创建用户失败"})
 		return
 	}
@@ -126,6 +214,20 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, gin.H{"message": "用户创建成功", "id": newUser.ID})
 }
 
+// UpdateUser @Summary 更新用户信息
+// @Description 更新指定用户的信息，需要管理员权限
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param user body UpdateUserRequest true "用户信息"
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
+// @Success 200 {object} UpdateUserResponse "用户信息更新成功"
+// @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
+// @Failure 500 {object} ErrorResponse "更新用户信息失败"
+// @Security ApiKeyAuth
+// @Router /api/users/{id} [put]
 // UpdateUser 更新用户信息
 func (c *UserController) UpdateUser(ctx *gin.Context) {
 	userID, err := strconv.Atoi(ctx.Param("id"))
@@ -144,20 +246,67 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
 	user := &models.User{
 		ID:       userID,
 		Username: updateData.Username,
 		Password: updateData.Password,
+		Version:  version,
 	}
 
-	if err := c.userService.UpdateUser(user); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新用户信息失败"})
+	if err := c.userService.WithContext(ctx.Request.Context()).UpdateUser(user); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "更新用户信息失败"})
+		}
 		return
 	}
 
+	ctx.Header("ETag", versionETag(user.Version))
 	ctx.JSON(http.StatusOK, gin.H{"message": "用户信息更新成功"})
 }
 
+// GetDetail @Summary 获取用户详情
+// @Description 获取指定用户的基本信息，不含密码
+// @Tags 用户管理
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} models.User "用户详情"
+// @Header 200 {string} ETag "资源版本，修改用户或绑定角色时通过 If-Match 提交"
+// @Failure 400 {object} ErrorResponse "无效的用户ID"
+// @Failure 404 {object} ErrorResponse "用户不存在"
+// @Failure 500 {object} ErrorResponse "获取用户详情失败"
+// @Security ApiKeyAuth
+// @Router /api/users/detail/{id} [get]
+func (c *UserController) GetDetail(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	user, err := c.userService.WithContext(ctx.Request.Context()).GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取用户详情失败"})
+		return
+	}
+
+	ctx.Header("ETag", versionETag(user.Version))
+	ctx.JSON(http.StatusOK, user)
+}
+
 // BindRolesRequest 绑定角色请求参数
 type BindRolesRequest []int
 
@@ -176,8 +325,10 @@ type RouteResponse []services.RouteItem
 // @Produce json
 // @Param id path int true "用户ID"
 // @Param roleIDs body BindRolesRequest true "角色ID列表" example:[1,2,3]
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
 // @Success 200 {object} BindRolesResponse "角色绑定成功"
 // @Failure 400 {object} ErrorResponse "无效的请求参数"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
 // @Failure 500 {object} ErrorResponse "绑定角色失败"
 // @Security ApiKeyAuth
 // @Router /api/users/{id}/roles [post]
@@ -195,14 +346,105 @@ func (c *UserController) BindRoles(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.userService.BindUserRoles(userID, roleIDs); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "绑定角色失败"})
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.userService.WithContext(ctx.Request.Context()).BindUserRoles(userID, version, roleIDs); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		case errors.Is(err, services.ErrRoleNotFound):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "绑定角色失败"})
+		}
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "角色绑定成功"})
 }
 
+// PatchRolesRequest 增量修改用户角色请求参数
+type PatchRolesRequest struct {
+	Add    []int `json:"add" example:"1,2"`  // 添加的角色ID，已绑定的忽略
+	Remove []int `json:"remove" example:"3"` // 移除的角色ID，未绑定的忽略
+}
+
+// PatchRolesResponse 增量修改用户角色响应
+type PatchRolesResponse struct {
+	RoleIDs []int `json:"role_ids" example:"1,2"` // 修改后用户的全部角色ID
+}
+
+// PatchRoles @Summary 增量修改用户角色
+// @Description 为用户添加或移除指定角色，不影响其他已绑定的角色，重复提交结果相同。返回修改后用户的全部角色ID。需要一次性替换全部角色时使用 POST 接口
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param request body PatchRolesRequest true "添加和移除的角色ID"
+// @Param If-Match header string false "详情接口返回的 ETag，与当前版本不一致时返回 412"
+// @Success 200 {object} PatchRolesResponse "修改后的角色ID"
+// @Failure 400 {object} ErrorResponse "无效的请求参数、角色不存在或同一角色同时添加和移除"
+// @Failure 404 {object} ErrorResponse "用户不存在"
+// @Failure 412 {object} ErrorResponse "资源已被他人修改"
+// @Failure 500 {object} ErrorResponse "修改角色失败"
+// @Security ApiKeyAuth
+// @Router /api/users/{id}/roles [patch]
+func (c *UserController) PatchRoles(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	var req PatchRolesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的请求参数"})
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	roleIDs, err := c.userService.WithContext(ctx.Request.Context()).PatchUserRoles(userID, version, req.Add, req.Remove)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		case errors.Is(err, services.ErrRoleNotFound), errors.Is(err, services.ErrAddRemoveConflict):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "修改角色失败"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PatchRolesResponse{RoleIDs: roleIDs})
+}
+
+// GetUsersRequest 获取用户列表请求参数
+type GetUsersRequest struct {
+	ListRequest
+	RoleID *int `json:"role_id" example:"1"` // 按角色筛选
+}
+
+// GetUsersResponse 用户列表响应
+type GetUsersResponse struct {
+	Data       []models.User `json:"data"`
+	Total      int64         `json:"total"` // 游标分页时不返回
+	Page       int           `json:"page"`
+	PageSize   int           `json:"pageSize"`
+	NextCursor string        `json:"next_cursor,omitempty"` // 游标分页的下一页游标，为空表示没有更多数据
+}
+
 // PageUsers @Summary 获取用户列表
 // @Description 获取用户列表，支持分页
 // @Tags 用户管理
@@ -213,12 +455,8 @@ func (c *UserController) BindRoles(ctx *gin.Context) {
 // @Failure 400 {object} ErrorResponse "无效的请求参数"
 // @Failure 500 {object} ErrorResponse "获取用户列表失败"
 // @Security ApiKeyAuth
-// @Router /api/users [post]
-type GetUsersRequest struct {
-	Page     int `json:"page" example:"1" binding:"required"`
-	PageSize int `json:"pageSize" example:"10" binding:"required"`
-}
-
+// @Router /api/users/page [post]
+// PageUsers 获取用户列表
 func (c *UserController) PageUsers(ctx *gin.Context) {
 	var req GetUsersRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -227,18 +465,16 @@ func (c *UserController) PageUsers(ctx *gin.Context) {
 		return
 	}
 
-	users, total, err := c.userService.PageUsers(req.Page, req.PageSize)
+	query := req.toQuery(map[string]interface{}{
+		"role_id": optionalInt(req.RoleID),
+	})
+	users, info, err := c.userService.WithContext(ctx.Request.Context()).PageUsers(query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取用户列表失败"})
+		respondListError(ctx, err, "获取用户列表失败")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"data":     users,
-		"total":    total,
-		"page":     req.Page,
-		"pageSize": req.PageSize,
-	})
+	ctx.JSON(http.StatusOK, listResponse(users, req.ListRequest, info))
 }
 
 // GetRoutes @Summary 获取用户路由数据
@@ -258,7 +494,7 @@ func (c *UserController) GetRoutes(ctx *gin.Context) {
 		return
 	}
 
-	routes, err := c.userService.GetUserRoutes(userID)
+	routes, err := c.userService.WithContext(ctx.Request.Context()).GetUserRoutes(userID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取路由数据失败"})
 		return
@@ -266,3 +502,74 @@ func (c *UserController) GetRoutes(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, routes)
 }
+
+// GetMyPermissions @Summary 获取当前用户的权限编码
+// @Description 返回当前用户生效的菜单和按钮权限编码（通配权限已展开，被拒绝的权限已排除），供前端控制按钮显示。响应头 ETag 为权限版本，请求头 If-None-Match 与之相同时返回 304
+// @Tags 用户管理
+// @Produce json
+// @Param If-None-Match header string false "上次响应的 ETag"
+// @Success 200 {object} services.UserPermissions "权限编码"
+// @Success 304 "权限未变化"
+// @Failure 400 {object} ErrorResponse "无效的用户ID"
+// @Failure 500 {object} ErrorResponse "获取权限编码失败"
+// @Security ApiKeyAuth
+// @Router /api/users/me/permissions [get]
+func (c *UserController) GetMyPermissions(ctx *gin.Context) {
+	userID := ctx.GetInt("user_id")
+	if userID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	permissions, err := c.userService.WithContext(ctx.Request.Context()).GetUserPermissions(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取权限编码失败"})
+		return
+	}
+
+	// 权限随角色绑定变化，客户端每次都需要向服务端确认版本
+	etag := `"` + permissions.Version + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "private, no-cache")
+	if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, permissions)
+}
+
+// DeleteUser @Summary 删除用户
+// @Description 删除指定用户，同时清理用户的角色关联并吊销其刷新令牌，不能删除当前登录用户。删除后进入回收站，可恢复
+// @Tags 用户管理
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} object "用户删除成功"
+// @Failure 400 {object} ErrorResponse "无效的用户ID"
+// @Failure 404 {object} ErrorResponse "用户不存在"
+// @Failure 500 {object} ErrorResponse "删除用户失败"
+// @Security ApiKeyAuth
+// @Router /api/users/{id} [delete]
+func (c *UserController) DeleteUser(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID"})
+		return
+	}
+
+	if userID == ctx.GetInt("user_id") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "不能删除当前登录用户"})
+		return
+	}
+
+	if err := c.userService.WithContext(ctx.Request.Context()).DeleteUser(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "删除用户失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "用户删除成功"})
+}
@@ -15,14 +15,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/buttons": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "以JWKS格式公布校验访问令牌所需的公钥，包括即将轮换启用的密钥，下游服务按令牌头部的kid选择公钥",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "公钥集合",
+                        "schema": {
+                            "$ref": "#/definitions/keyring.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/audit-logs/page": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "创建新按钮，需要管理员权限",
+                "description": "按操作人、资源、操作类型、请求ID和时间范围筛选审计日志，支持分页和排序，默认按时间倒序",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,49 +49,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "按钮管理"
+                    "审计日志"
                 ],
                 "parameters": [
                     {
-                        "description": "按钮信息",
-                        "name": "button",
+                        "description": "筛选和分页参数",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.GetAuditLogsRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "按钮创建成功",
+                    "200": {
+                        "description": "审计日志列表",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.GetAuditLogsResponse"
                         }
                     },
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "创建按钮失败",
+                        "description": "获取审计日志失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/buttons/menu/{menuId}": {
-            "get": {
+        "/api/authz/check": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "获取指定菜单的所有按钮列表，需要管理员权限",
+                "description": "判定用户是否拥有指定权限，返回是否允许、判定原因和决定结果的角色。reason 为 super_admin、granted、denied（被角色显式拒绝）、not_granted、tenant_inactive 或 subject_not_found。非平台租户只能判定本租户的用户",
                 "consumes": [
                     "application/json"
                 ],
@@ -80,50 +99,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "按钮管理"
+                    "权限判定"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "菜单ID",
-                        "name": "menuId",
-                        "in": "path",
-                        "required": true
+                        "description": "判定请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AuthzCheckRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "按钮列表",
+                        "description": "判定结果",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Button"
-                            }
+                            "$ref": "#/definitions/services.AuthzResult"
                         }
                     },
                     "400": {
-                        "description": "无效的菜单ID",
+                        "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "无权判定其他租户的用户",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "获取按钮列表失败",
+                        "description": "权限判定失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/buttons/{id}": {
-            "put": {
+        "/api/authz/check/batch": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "更新指定按钮的信息，需要管理员权限",
+                "description": "一次判定多条权限请求，结果顺序与请求一致，同一用户的请求只加载一次授权数据。判定规则与单条判定相同",
                 "consumes": [
                     "application/json"
                 ],
@@ -131,56 +155,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "按钮管理"
+                    "权限判定"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "按钮ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "按钮信息",
-                        "name": "button",
+                        "description": "判定请求列表",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.AuthzBatchCheckRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "按钮信息更新成功",
+                        "description": "判定结果",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.AuthzBatchCheckResponse"
                         }
                     },
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "无权判定其他租户的用户",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "更新按钮信息失败",
+                        "description": "权限判定失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/buttons/{id}/permission": {
+        "/api/authz/explain": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "为指定按钮绑定权限，需要管理员权限",
+                "description": "给定用户和权限编码、菜单ID或按钮ID之一，列出授予或拒绝它的全部路径：用户角色 →（继承的角色）→ 绑定的权限 →（通配匹配、上下级展开得到的权限），并给出最终结果和起决定作用的角色。拒绝路径在前，被拒绝覆盖的授予路径 outcome 为 overridden。菜单的任一子孙菜单被授权时菜单同样可见，相应路径一并列出",
                 "consumes": [
                     "application/json"
                 ],
@@ -188,56 +211,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "按钮管理"
+                    "权限判定"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "按钮ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "权限信息",
-                        "name": "permission",
+                        "description": "解释请求",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.AuthzExplainRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "权限绑定成功",
+                        "description": "授权路径",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/services.Explanation"
                         }
                     },
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "绑定权限失败",
+                        "description": "解释授权失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/buttons/{id}/permissions": {
-            "get": {
+        "/api/buttons": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "获取指定按钮的权限列表，需要管理员权限",
+                "description": "创建新按钮，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -249,41 +271,45 @@ const docTemplate = `{
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "按钮ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "按钮信息",
+                        "name": "button",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateButtonRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "权限列表",
+                    "201": {
+                        "description": "按钮创建成功",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Permission"
-                            }
+                            "$ref": "#/definitions/controllers.CreateButtonResponse"
                         }
                     },
                     "400": {
-                        "description": "无效的按钮ID",
+                        "description": "无效的请求参数或所属菜单不存在",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "获取按钮权限列表失败",
+                        "description": "创建按钮失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/login": {
-            "post": {
-                "description": "用户登录接口，验证用户名和密码，返回JWT token",
+        "/api/buttons/detail/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取指定按钮的详细信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -291,49 +317,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
+                    "按钮管理"
                 ],
                 "parameters": [
                     {
-                        "description": "登录信息",
-                        "name": "loginData",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
+                        "type": "integer",
+                        "description": "按钮ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "登录成功，返回token",
+                        "description": "按钮详情",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/models.Button"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "资源版本，修改时通过 If-Match 提交"
+                            }
                         }
                     },
                     "400": {
-                        "description": "无效的请求参数",
+                        "description": "无效的按钮ID",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "用户名或密码错误",
+                    "500": {
+                        "description": "获取按钮详情失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/menus": {
+        "/api/buttons/menu/{menuId}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "获取所有菜单的列表，需要管理员权限",
+                "description": "获取指定菜单的所有按钮列表，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -341,33 +371,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "按钮管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "菜单ID",
+                        "name": "menuId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "菜单列表",
+                        "description": "按钮列表",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Menu"
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.Button"
+                                }
                             }
                         }
                     },
+                    "400": {
+                        "description": "无效的菜单ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "获取菜单列表失败",
+                        "description": "获取按钮列表失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/buttons/page": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "创建新菜单，需要管理员权限",
+                "description": "获取所有按钮的列表，支持分页，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -375,49 +425,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "按钮管理"
                 ],
                 "parameters": [
                     {
-                        "description": "菜单信息",
-                        "name": "menu",
+                        "description": "分页参数",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.GetButtonsRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "菜单创建成功",
+                    "200": {
+                        "description": "按钮列表",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.GetButtonsResponse"
                         }
                     },
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "创建菜单失败",
+                        "description": "获取按钮列表失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/menus/parent/{parentId}": {
-            "get": {
+        "/api/buttons/{id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "获取指定父级菜单的子菜单列表，需要管理员权限",
+                "description": "更新指定按钮的信息，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -425,80 +475,96 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "按钮管理"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "父级菜单ID",
-                        "name": "parentId",
+                        "description": "按钮ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "按钮信息",
+                        "name": "button",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateButtonRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "子菜单列表",
+                        "description": "按钮信息更新成功",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Menu"
-                            }
+                            "$ref": "#/definitions/controllers.UpdateButtonResponse"
                         }
                     },
                     "400": {
-                        "description": "无效的父级菜单ID",
+                        "description": "无效的请求参数或所属菜单不存在",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "按钮不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "获取子菜单列表失败",
+                        "description": "更新按钮信息失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/menus/{id}": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "更新指定菜单的信息，需要管理员权限",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "删除指定按钮。按钮绑定了权限时默认拒绝删除；传入 cascade=true 时一并删除这些权限及其角色关联。删除后进入回收站，可恢复",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "按钮管理"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "菜单ID",
+                        "description": "按钮ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "菜单信息",
-                        "name": "menu",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
+                        "type": "boolean",
+                        "description": "是否级联删除",
+                        "name": "cascade",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "菜单信息更新成功",
+                        "description": "按钮删除成功",
                         "schema": {
                             "type": "object"
                         }
@@ -506,26 +572,38 @@ const docTemplate = `{
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "按钮不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "按钮已绑定权限，不能删除",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "更新菜单信息失败",
+                        "description": "删除按钮失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/menus/{id}/permission": {
+        "/api/buttons/{id}/permission": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "为指定菜单绑定权限，需要管理员权限",
+                "description": "为指定按钮绑定权限，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -533,12 +611,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "按钮管理"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "菜单ID",
+                        "description": "按钮ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -549,7 +627,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.BindButtonPermissionRequest"
                         }
                     }
                 ],
@@ -557,32 +635,32 @@ const docTemplate = `{
                     "200": {
                         "description": "权限绑定成功",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.BindButtonPermissionResponse"
                         }
                     },
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "绑定权限失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/menus/{id}/permissions": {
+        "/api/buttons/{id}/permissions": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "获取指定菜单的权限列表，需要管理员权限",
+                "description": "获取指定按钮的权限列表，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -590,12 +668,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "菜单管理"
+                    "按钮管理"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "菜单ID",
+                        "description": "按钮ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -607,67 +685,31 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Permission"
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.Permission"
+                                }
                             }
                         }
                     },
                     "400": {
-                        "description": "无效的菜单ID",
+                        "description": "无效的按钮ID",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "获取菜单权限列表失败",
+                        "description": "获取按钮权限列表失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/permissions": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "获取所有权限的列表，需要管理员权限",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "权限管理"
-                ],
-                "responses": {
-                    "200": {
-                        "description": "权限列表",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Permission"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "获取权限列表失败",
-                        "schema": {
-                            "type": "object"
-                        }
-                    }
-                }
-            },
+        "/api/login": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "创建新权限，需要管理员权限",
+                "description": "用户登录接口，验证用户名和密码，返回访问令牌和刷新令牌",
                 "consumes": [
                     "application/json"
                 ],
@@ -675,49 +717,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "权限管理"
+                    "用户管理"
                 ],
                 "parameters": [
                     {
-                        "description": "权限信息",
-                        "name": "permission",
+                        "description": "登录信息",
+                        "name": "loginData",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "权限创建成功",
+                    "200": {
+                        "description": "登录成功，返回token",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.LoginResponse"
                         }
                     },
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "创建权限失败",
+                    "401": {
+                        "description": "用户名或密码错误",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/permissions/type/{type}": {
-            "get": {
+        "/api/logout": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "获取指定类型的所有权限列表，需要管理员权限",
+                "description": "吊销当前访问令牌，传入刷新令牌时一并吊销该登录会话的全部刷新令牌",
                 "consumes": [
                     "application/json"
                 ],
@@ -725,44 +767,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "权限管理"
+                    "用户管理"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "权限类型",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
+                        "description": "刷新令牌",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LogoutRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "权限列表",
+                        "description": "退出成功",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Permission"
-                            }
+                            "type": "object"
                         }
                     },
                     "500": {
-                        "description": "获取权限列表失败",
+                        "description": "退出登录失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/permissions/{id}": {
-            "put": {
+        "/api/menus": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "更新指定权限的信息，需要管理员权限",
+                "description": "创建新菜单，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -770,56 +810,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "权限管理"
+                    "菜单管理"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "权限ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "权限信息",
-                        "name": "permission",
+                        "description": "菜单信息",
+                        "name": "menu",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.CreateMenuRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "权限信息更新成功",
+                    "201": {
+                        "description": "菜单创建成功",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.CreateMenuResponse"
                         }
                     },
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "更新权限信息失败",
+                        "description": "创建菜单失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/roles": {
+        "/api/menus/detail/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "获取所有角色的列表，需要管理员权限",
+                "description": "获取指定菜单的详细信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -827,33 +860,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "菜单管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "菜单ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "角色列表",
+                        "description": "菜单详情",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Role"
+                            "$ref": "#/definitions/models.Menu"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "资源版本，修改时通过 If-Match 提交"
                             }
                         }
                     },
+                    "400": {
+                        "description": "无效的菜单ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "获取角色列表失败",
+                        "description": "获取菜单详情失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/menus/page": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "创建新角色，需要管理员权限",
+                "description": "获取所有菜单的列表，支持分页，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -861,49 +914,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "菜单管理"
                 ],
                 "parameters": [
                     {
-                        "description": "角色信息",
-                        "name": "role",
+                        "description": "分页参数",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.GetMenusRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "角色创建成功",
+                    "200": {
+                        "description": "菜单列表",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.GetMenusResponse"
                         }
                     },
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "创建角色失败",
+                        "description": "获取菜单列表失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/roles/{id}": {
-            "put": {
+        "/api/menus/parent/{parentId}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "更新指定角色的信息，需要管理员权限",
+                "description": "获取指定父级菜单的子菜单列表，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -911,105 +964,86 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "菜单管理"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "角色ID",
-                        "name": "id",
+                        "description": "父级菜单ID",
+                        "name": "parentId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "角色信息",
-                        "name": "role",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "角色信息更新成功",
+                        "description": "子菜单列表",
                         "schema": {
-                            "type": "object"
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.Menu"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "无效的请求参数",
+                        "description": "无效的父级菜单ID",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "更新角色信息失败",
+                        "description": "获取子菜单列表失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/roles/{id}/permissions": {
+        "/api/menus/tree": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "获取指定角色的所有权限列表，需要管理员权限",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "获取当前租户的完整菜单树，同级菜单按排序序号排列",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
-                ],
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "角色ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "菜单管理"
                 ],
                 "responses": {
                     "200": {
-                        "description": "权限列表",
+                        "description": "菜单树",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Permission"
+                                "$ref": "#/definitions/services.MenuTreeNode"
                             }
                         }
                     },
-                    "400": {
-                        "description": "无效的角色ID",
-                        "schema": {
-                            "type": "object"
-                        }
-                    },
                     "500": {
-                        "description": "获取角色权限列表失败",
+                        "description": "获取菜单树失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/menus/{id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "为指定角色绑定一个或多个权限，需要管理员权限",
+                "description": "更新指定菜单的信息，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -1017,32 +1051,96 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "角色管理"
+                    "菜单管理"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "角色ID",
+                        "description": "菜单ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "权限ID列表",
-                        "name": "permissionIDs",
+                        "description": "菜单信息",
+                        "name": "menu",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/controllers.UpdateMenuRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "权限绑定成功",
+                        "description": "菜单信息更新成功",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateMenuResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数或上级菜单不合法",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "菜单不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "更新菜单信息失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "删除指定菜单。菜单存在子菜单、按钮或权限时默认拒绝删除；传入 cascade=true 时一并删除所有子菜单、按钮、权限及权限的角色关联。删除后进入回收站，可恢复",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "菜单管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "菜单ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否级联删除",
+                        "name": "cascade",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "菜单删除成功",
                         "schema": {
                             "type": "object"
                         }
@@ -1050,26 +1148,38 @@ const docTemplate = `{
                     "400": {
                         "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "菜单不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "菜单仍被引用，不能删除",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "绑定权限失败",
+                        "description": "删除菜单失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/users": {
+        "/api/menus/{id}/move": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "创建新用户，需要管理员权限",
+                "description": "将菜单移动到指定上级菜单下的指定位置，新旧同级菜单的排序序号自动重新编排；不能移动到自身或其子菜单下",
                 "consumes": [
                     "application/json"
                 ],
@@ -1077,88 +1187,119 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
+                    "菜单管理"
                 ],
                 "parameters": [
                     {
-                        "description": "用户信息",
-                        "name": "user",
+                        "type": "integer",
+                        "description": "菜单ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "目标位置",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.MoveMenuRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "用户创建成功",
+                    "200": {
+                        "description": "菜单移动成功",
                         "schema": {
                             "type": "object"
                         }
                     },
                     "400": {
-                        "description": "无效的请求参数",
+                        "description": "无效的请求参数或目标上级菜单不合法",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "菜单不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "创建用户失败",
+                        "description": "移动菜单失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/users/routes": {
-            "get": {
+        "/api/menus/{id}/permission": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "获取用户的路由数据，包括菜单和权限信息",
+                "description": "为指定菜单绑定权限，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
+                    "菜单管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "菜单ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "权限信息",
+                        "name": "permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BindMenuPermissionRequest"
+                        }
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "路由数据",
+                        "description": "权限绑定成功",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/services.RouteItem"
-                            }
+                            "$ref": "#/definitions/controllers.BindMenuPermissionResponse"
                         }
                     },
                     "400": {
-                        "description": "无效的用户ID",
+                        "description": "无效的请求参数",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "获取路由数据失败",
+                        "description": "绑定权限失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/users/{id}": {
-            "put": {
+        "/api/menus/{id}/permissions": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "更新指定用户的信息，需要管理员权限",
+                "description": "获取指定菜单的权限列表，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -1166,127 +1307,3994 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
+                    "菜单管理"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "用户ID",
+                        "description": "菜单ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "权限列表",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.Permission"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "无效的菜单ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "获取菜单权限列表失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/permissions": {
+            "post": {
+                "security": [
                     {
-                        "description": "用户信息",
-                        "name": "user",
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "创建新权限，需要管理员权限。权限编码由冒号分隔的段组成（如 user:create），段内可用 * 通配（如 user:*、*:read），授予通配权限即授予其匹配的全部权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限管理"
+                ],
+                "parameters": [
+                    {
+                        "description": "权限信息",
+                        "name": "permission",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.CreatePermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "权限创建成功",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreatePermissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数、权限编码格式不合法，或父级权限、所属菜单、按钮不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "创建权限失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/permissions/detail/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取指定权限的详细信息，包括基本信息、关联的菜单和按钮信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "权限ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "用户信息更新成功",
+                        "description": "权限详情",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/models.Permission"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "资源版本，修改时通过 If-Match 提交"
+                            }
                         }
                     },
                     "400": {
-                        "description": "无效的请求参数",
+                        "description": "无效的权限ID",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "权限不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "更新用户信息失败",
+                        "description": "获取权限详情失败",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/controllers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/users/{id}/roles": {
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "为指定用户绑定一个或多个角色，需要管理员权限",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "用户管理"
-                ],
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "用户ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "角色ID列表",
-                        "name": "roleIDs",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer"
-                            }
-                        }
+        "/api/permissions/expand": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "返回通配编码当前匹配的具体权限，按编码排序。* 只在段内匹配，不跨越冒号，如 user:* 匹配 user:create 但不匹配 user:profile:edit",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "user:*",
+                        "description": "权限编码，可包含 * 通配",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "匹配的权限",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ExpandPermissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "权限编码格式不合法",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "展开权限编码失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/permissions/page": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取所有权限的列表，支持分页，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限管理"
+                ],
+                "parameters": [
+                    {
+                        "description": "分页参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetPermissionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "权限列表",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetPermissionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取权限列表失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/permissions/type/{type}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取指定类型的所有权限列表，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "权限类型",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "权限列表",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.Permission"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "获取权限列表失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/permissions/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "更新指定权限的信息，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "权限ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "权限信息",
+                        "name": "permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdatePermissionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "权限信息更新成功",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdatePermissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数、权限编码格式不合法、父级权限不合法，或所属菜单、按钮不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "权限不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "更新权限信息失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "删除指定权限，同时解除该权限与角色的关联；存在子权限时拒绝删除。删除后进入回收站，可恢复",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "权限ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "权限删除成功",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的权限ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "权限不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "权限存在子权限，不能删除",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "删除权限失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recycle-bin/page": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "分页获取回收站中指定类型的记录，支持按名称搜索，默认按删除时间倒序；游标分页按删除时间排序",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "回收站"
+                ],
+                "parameters": [
+                    {
+                        "description": "分页参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetRecycleBinRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "回收站列表",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetRecycleBinResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取回收站列表失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recycle-bin/{type}/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "彻底删除回收站中的记录，同一次删除中一并删除的记录随之彻底删除，操作不可恢复",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "回收站"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "资源类型：user、role、permission、menu、button",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "记录ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "彻底删除成功",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "回收站中不存在该记录",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "彻底删除失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recycle-bin/{type}/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "从回收站恢复记录，同一次删除中一并删除的子菜单、按钮、权限随之恢复，删除时解除的用户角色、角色权限关联也会重新建立",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "回收站"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "资源类型：user、role、permission、menu、button",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "记录ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "恢复成功",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "回收站中不存在该记录",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "上级记录仍在回收站中，或已存在相同用户名或编码的记录",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "恢复失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "创建新角色，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "description": "角色信息",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "角色创建成功",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数，或使用了保留的超级管理员角色编码",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "创建角色失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/detail/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取指定角色的详细信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "角色详情",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "资源版本，修改时通过 If-Match 提交"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "无效的角色ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取角色详情失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/page": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取所有角色的列表，支持分页，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "description": "分页参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetRolesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "角色列表",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetRolesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取角色列表失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "更新指定角色的信息，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "角色信息",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "角色信息更新成功",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数，或将编码改为保留的超级管理员角色编码",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "角色不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "更新角色信息失败",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "删除指定角色，同时解除该角色与用户、权限的关联以及与其他角色的继承关系。删除后进入回收站，可恢复",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "角色删除成功",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的角色ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "角色不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "删除角色失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}/bindPermissions": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "用给定的权限替换角色当前绑定的全部权限，需要管理员权限。每一项可以是权限编码或权限ID，找不到的条目在 unknown 中返回并被忽略，strict 为 true 时整体失败并返回 400。deny 中的权限为显式拒绝，用户的任一角色拒绝某权限时，其他角色的授予不再生效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "授予和拒绝的权限编码或ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BindPermissionsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "替换后的权限绑定及找不到的条目",
+                        "schema": {
+                            "$ref": "#/definitions/services.BindPermissionsResult"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数、同一权限同时出现在授予和拒绝中，或严格模式下存在找不到的权限",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "角色不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "绑定权限失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}/effective-permissions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "列出角色授予和拒绝的全部权限（按授予模式展开），state 为 allowed 或 denied，own 表示当前状态来自角色自身的绑定，inherited_from 列出决定当前状态的祖先角色ID；同时返回直接父角色和全部祖先角色",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "角色生效的权限",
+                        "schema": {
+                            "$ref": "#/definitions/services.RoleEffectivePermissions"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的角色ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "角色不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取角色权限失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}/parents": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "用给定的父角色列表替换角色当前的继承关系，角色拥有父角色及其全部祖先角色的权限。可以继承多个父角色，继承关系不能形成环",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "父角色ID列表",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BindParentsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "父角色设置成功",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数、父角色不存在或继承关系形成环",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "角色不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "设置父角色失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{id}/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取完整的权限树并标记角色拥有的权限，按层级结构返回。enable 按配置的授予模式计算（授予父权限是否包含子孙权限、授予子权限是否包含上级权限），包括从父角色继承的权限。state 为 allowed、denied 或 unset，拒绝优先于授予，enable 即 state 为 allowed；own 表示当前状态来自角色自身的绑定，inherited_from 列出决定当前状态的祖先角色ID；half_checked 表示子孙权限只授予了一部分",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "权限树形列表",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/services.PermissionTreeNode"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "无效的角色ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "角色不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取角色权限列表失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "为角色授予、拒绝或解除指定权限，不影响其他已绑定的权限，重复提交结果相同。返回修改后角色的全部权限绑定。需要一次性替换全部权限时使用 bindPermissions 接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "角色管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "授予、拒绝和解除的权限ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PatchPermissionsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改后的权限绑定",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PatchPermissionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数、权限不存在或同一权限出现在多个列表中",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "角色不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "修改权限失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tenants": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "创建新租户，可同时创建租户的初始管理员账号，仅平台租户可访问",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户管理"
+                ],
+                "parameters": [
+                    {
+                        "description": "租户信息",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "租户创建成功",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateTenantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "创建租户失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tenants/detail/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取指定租户的详细信息，仅平台租户可访问",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "租户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "租户详情",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的租户ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "租户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取租户详情失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tenants/page": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取所有租户的列表，支持分页，仅平台租户可访问",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户管理"
+                ],
+                "parameters": [
+                    {
+                        "description": "分页参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetTenantsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "租户列表",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetTenantsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取租户列表失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tenants/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "更新指定租户的名称和状态，停用后该租户的用户无法登录，仅平台租户可访问",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "租户管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "租户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "租户信息",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "租户信息更新成功",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "更新租户信息失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/token/refresh": {
+            "post": {
+                "description": "使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌立即失效；已使用过的刷新令牌再次提交时整个登录会话将被吊销",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "parameters": [
+                    {
+                        "description": "刷新令牌",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "刷新成功，返回新的令牌",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "刷新令牌无效或已被使用",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "创建新用户，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "parameters": [
+                    {
+                        "description": "用户信息",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "用户创建成功",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "创建用户失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/detail/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取指定用户的基本信息，不含密码",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "用户详情",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "资源版本，修改用户或绑定角色时通过 If-Match 提交"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "无效的用户ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取用户详情失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/me/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "返回当前用户生效的菜单和按钮权限编码（通配权限已展开，被拒绝的权限已排除），供前端控制按钮显示。响应头 ETag 为权限版本，请求头 If-None-Match 与之相同时返回 304",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "上次响应的 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "权限编码",
+                        "schema": {
+                            "$ref": "#/definitions/services.UserPermissions"
+                        }
+                    },
+                    "304": {
+                        "description": "权限未变化"
+                    },
+                    "400": {
+                        "description": "无效的用户ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取权限编码失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/page": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取用户列表，支持分页",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "parameters": [
+                    {
+                        "description": "分页参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "用户列表",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GetUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取用户列表失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/routes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "获取用户的路由数据，包括菜单和权限信息",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "路由数据",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/services.RouteItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "无效的用户ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "获取路由数据失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "更新指定用户的信息，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "用户信息",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "用户信息更新成功",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "更新用户信息失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "删除指定用户，同时清理用户的角色关联并吊销其刷新令牌，不能删除当前登录用户。删除后进入回收站，可恢复",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "用户删除成功",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的用户ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "删除用户失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/roles": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "为指定用户绑定一个或多个角色，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "角色ID列表",
+                        "name": "roleIDs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "角色绑定成功",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BindRolesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "绑定角色失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "为用户添加或移除指定角色，不影响其他已绑定的角色，重复提交结果相同。返回修改后用户的全部角色ID。需要一次性替换全部角色时使用 POST 接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "添加和移除的角色ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PatchRolesRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "详情接口返回的 ETag，与当前版本不一致时返回 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改后的角色ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PatchRolesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的请求参数、角色不存在或同一角色同时添加和移除",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "用户不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "资源已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "修改角色失败",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.AuthzBatchCheckRequest": {
+            "type": "object",
+            "required": [
+                "checks"
+            ],
+            "properties": {
+                "checks": {
+                    "description": "判定请求，最多100条",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/controllers.AuthzCheckRequest"
+                    }
+                }
+            }
+        },
+        "controllers.AuthzBatchCheckResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "description": "与请求一一对应",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.AuthzResult"
+                    }
+                }
+            }
+        },
+        "controllers.AuthzCheckRequest": {
+            "type": "object",
+            "required": [
+                "permission",
+                "subject"
+            ],
+            "properties": {
+                "permission": {
+                    "description": "权限编码",
+                    "type": "string",
+                    "example": "user:create"
+                },
+                "resource": {
+                    "description": "资源标识，目前仅原样返回，判定只依据权限编码",
+                    "type": "string",
+                    "example": "order:1024"
+                },
+                "subject": {
+                    "description": "用户ID",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "tenant_id": {
+                    "description": "用户所属租户，不传时为调用方所在租户",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "controllers.AuthzExplainRequest": {
+            "type": "object",
+            "required": [
+                "subject"
+            ],
+            "properties": {
+                "button_id": {
+                    "description": "按钮ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "menu_id": {
+                    "description": "菜单ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "permission": {
+                    "description": "权限编码",
+                    "type": "string",
+                    "example": "user:create"
+                },
+                "subject": {
+                    "description": "用户ID",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                }
+            }
+        },
+        "controllers.BindButtonPermissionRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "permission_code"
+            ],
+            "properties": {
+                "name": {
+                    "description": "权限名称",
+                    "type": "string",
+                    "example": "新增按钮"
+                },
+                "permission_code": {
+                    "description": "权限编码",
+                    "type": "string",
+                    "example": "button:add"
+                }
+            }
+        },
+        "controllers.BindButtonPermissionResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "权限绑定成功"
+                }
+            }
+        },
+        "controllers.BindMenuPermissionRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "permission_code"
+            ],
+            "properties": {
+                "name": {
+                    "description": "权限名称",
+                    "type": "string",
+                    "example": "查看菜单"
+                },
+                "permission_code": {
+                    "description": "权限编码",
+                    "type": "string",
+                    "example": "menu:view"
+                }
+            }
+        },
+        "controllers.BindMenuPermissionResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "权限绑定成功"
+                }
+            }
+        },
+        "controllers.BindParentsRequest": {
+            "type": "object",
+            "properties": {
+                "parent_ids": {
+                    "description": "父角色ID列表，传空列表表示不再继承任何角色",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "controllers.BindPermissionsRequest": {
+            "type": "object",
+            "required": [
+                "permissions"
+            ],
+            "properties": {
+                "deny": {
+                    "description": "显式拒绝的权限编码或ID",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user:delete"
+                    ]
+                },
+                "permissions": {
+                    "description": "授予的权限编码或ID",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user:create",
+                        "5"
+                    ]
+                },
+                "strict": {
+                    "description": "为 true 时存在找不到的权限即整体失败，不做修改",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "controllers.BindRolesResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "角色绑定成功"
+                }
+            }
+        },
+        "controllers.CreateButtonRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "menu_id",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "description": "按钮编码",
+                    "type": "string",
+                    "example": "add"
+                },
+                "menu_id": {
+                    "description": "所属菜单ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "按钮名称",
+                    "type": "string",
+                    "example": "新增"
+                }
+            }
+        },
+        "controllers.CreateButtonResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "按钮ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "按钮创建成功"
+                }
+            }
+        },
+        "controllers.CreateMenuRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "path"
+            ],
+            "properties": {
+                "icon": {
+                    "description": "菜单图标",
+                    "type": "string",
+                    "example": "setting"
+                },
+                "name": {
+                    "description": "菜单名称",
+                    "type": "string",
+                    "example": "系统管理"
+                },
+                "order": {
+                    "description": "排序序号",
+                    "type": "integer",
+                    "example": 1
+                },
+                "parent_id": {
+                    "description": "父级菜单ID",
+                    "type": "integer",
+                    "example": 0
+                },
+                "path": {
+                    "description": "菜单路径",
+                    "type": "string",
+                    "example": "/system"
+                }
+            }
+        },
+        "controllers.CreateMenuResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "菜单ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "菜单创建成功"
+                }
+            }
+        },
+        "controllers.CreatePermissionRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name",
+                "type"
+            ],
+            "properties": {
+                "button_id": {
+                    "description": "按钮ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "code": {
+                    "description": "权限编码",
+                    "type": "string",
+                    "example": "user:create"
+                },
+                "menu_id": {
+                    "description": "菜单ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "权限名称",
+                    "type": "string",
+                    "example": "创建用户"
+                },
+                "parent_id": {
+                    "description": "父级权限ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "description": "权限类型",
+                    "type": "string",
+                    "example": "menu"
+                }
+            }
+        },
+        "controllers.CreatePermissionResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "权限ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "权限创建成功"
+                }
+            }
+        },
+        "controllers.CreateRoleRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "description": "角色编码，租户内唯一",
+                    "type": "string",
+                    "example": "ROLE_EDITOR"
+                },
+                "description": {
+                    "description": "角色描述",
+                    "type": "string",
+                    "example": "内容编辑角色"
+                },
+                "name": {
+                    "description": "角色名称",
+                    "type": "string",
+                    "example": "管理员"
+                }
+            }
+        },
+        "controllers.CreateRoleResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "角色ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "角色创建成功"
+                }
+            }
+        },
+        "controllers.CreateTenantRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "admin_password": {
+                    "description": "初始管理员密码，填写管理员用户名时必填",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "admin_username": {
+                    "description": "初始管理员用户名，可选",
+                    "type": "string",
+                    "example": "acme_admin"
+                },
+                "code": {
+                    "description": "租户编码",
+                    "type": "string",
+                    "example": "acme"
+                },
+                "name": {
+                    "description": "租户名称",
+                    "type": "string",
+                    "example": "ACME 公司"
+                }
+            }
+        },
+        "controllers.CreateTenantResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "租户ID",
+                    "type": "integer",
+                    "example": 2
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "租户创建成功"
+                }
+            }
+        },
+        "controllers.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "description": "邮箱",
+                    "type": "string",
+                    "example": "editor@example.com"
+                },
+                "password": {
+                    "description": "密码",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "phone": {
+                    "description": "手机号",
+                    "type": "string",
+                    "example": "13800000000"
+                },
+                "username": {
+                    "description": "用户名，未删除的用户中全局唯一",
+                    "type": "string",
+                    "example": "editor"
+                }
+            }
+        },
+        "controllers.CreateUserResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "用户ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "用户创建成功"
+                }
+            }
+        },
+        "controllers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "错误信息",
+                    "type": "string",
+                    "example": "无效的请求参数"
+                }
+            }
+        },
+        "controllers.ExpandPermissionResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "展开的通配编码",
+                    "type": "string",
+                    "example": "user:*"
+                },
+                "permissions": {
+                    "description": "当前匹配的具体权限",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Permission"
+                    }
+                }
+            }
+        },
+        "controllers.GetAuditLogsRequest": {
+            "type": "object",
+            "required": [
+                "pageSize"
+            ],
+            "properties": {
+                "action": {
+                    "description": "操作类型",
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor_id": {
+                    "description": "操作人ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_from": {
+                    "description": "创建时间起（含）",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00+08:00"
+                },
+                "created_to": {
+                    "description": "创建时间止（不含）",
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00+08:00"
+                },
+                "cursor": {
+                    "description": "游标分页的游标，游标分页不返回总数",
+                    "type": "string",
+                    "example": ""
+                },
+                "keyword": {
+                    "description": "关键字，模糊匹配名称、编码等字段",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "page": {
+                    "description": "页码分页的页码，默认第一页",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "pageSize": {
+                    "description": "每页条数，最大500",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "request_id": {
+                    "description": "请求ID",
+                    "type": "string",
+                    "example": ""
+                },
+                "resource_id": {
+                    "description": "资源ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "resource_type": {
+                    "description": "资源类型：user、role、permission、menu、button、tenant",
+                    "type": "string",
+                    "example": "role"
+                },
+                "sort": {
+                    "description": "排序字段，\"-\" 前缀表示倒序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "-created_at"
+                    ]
+                }
+            }
+        },
+        "controllers.GetAuditLogsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditLog"
+                    }
+                },
+                "next_cursor": {
+                    "description": "游标分页的下一页游标，为空表示没有更多数据",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "游标分页时不返回",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.GetButtonsRequest": {
+            "type": "object",
+            "required": [
+                "pageSize"
+            ],
+            "properties": {
+                "created_from": {
+                    "description": "创建时间起（含）",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00+08:00"
+                },
+                "created_to": {
+                    "description": "创建时间止（不含）",
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00+08:00"
+                },
+                "cursor": {
+                    "description": "游标分页的游标，游标分页不返回总数",
+                    "type": "string",
+                    "example": ""
+                },
+                "keyword": {
+                    "description": "关键字，模糊匹配名称、编码等字段",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "menu_id": {
+                    "description": "按所属菜单筛选",
+                    "type": "integer",
+                    "example": 1
+                },
+                "page": {
+                    "description": "页码分页的页码，默认第一页",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "pageSize": {
+                    "description": "每页条数，最大500",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "sort": {
+                    "description": "排序字段，\"-\" 前缀表示倒序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "-created_at"
+                    ]
+                }
+            }
+        },
+        "controllers.GetButtonsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Button"
+                    }
+                },
+                "next_cursor": {
+                    "description": "游标分页的下一页游标，为空表示没有更多数据",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "游标分页时不返回",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.GetMenusRequest": {
+            "type": "object",
+            "required": [
+                "pageSize"
+            ],
+            "properties": {
+                "created_from": {
+                    "description": "创建时间起（含）",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00+08:00"
+                },
+                "created_to": {
+                    "description": "创建时间止（不含）",
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00+08:00"
+                },
+                "cursor": {
+                    "description": "游标分页的游标，游标分页不返回总数",
+                    "type": "string",
+                    "example": ""
+                },
+                "is_visible": {
+                    "description": "按是否可见筛选",
+                    "type": "boolean",
+                    "example": true
+                },
+                "keyword": {
+                    "description": "关键字，模糊匹配名称、编码等字段",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "page": {
+                    "description": "页码分页的页码，默认第一页",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "pageSize": {
+                    "description": "每页条数，最大500",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "parent_id": {
+                    "description": "按父级菜单筛选",
+                    "type": "integer",
+                    "example": 0
+                },
+                "sort": {
+                    "description": "排序字段，\"-\" 前缀表示倒序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "-created_at"
+                    ]
+                }
+            }
+        },
+        "controllers.GetMenusResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Menu"
+                    }
+                },
+                "next_cursor": {
+                    "description": "游标分页的下一页游标，为空表示没有更多数据",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "游标分页时不返回",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.GetPermissionsRequest": {
+            "type": "object",
+            "required": [
+                "pageSize"
+            ],
+            "properties": {
+                "button_id": {
+                    "description": "按所属按钮筛选",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_from": {
+                    "description": "创建时间起（含）",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00+08:00"
+                },
+                "created_to": {
+                    "description": "创建时间止（不含）",
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00+08:00"
+                },
+                "cursor": {
+                    "description": "游标分页的游标，游标分页不返回总数",
+                    "type": "string",
+                    "example": ""
+                },
+                "keyword": {
+                    "description": "关键字，模糊匹配名称、编码等字段",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "menu_id": {
+                    "description": "按所属菜单筛选",
+                    "type": "integer",
+                    "example": 1
+                },
+                "page": {
+                    "description": "页码分页的页码，默认第一页",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "pageSize": {
+                    "description": "每页条数，最大500",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "parent_id": {
+                    "description": "按父级权限筛选",
+                    "type": "integer",
+                    "example": 1
+                },
+                "role_id": {
+                    "description": "按绑定的角色筛选",
+                    "type": "integer",
+                    "example": 1
+                },
+                "sort": {
+                    "description": "排序字段，\"-\" 前缀表示倒序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "-created_at"
+                    ]
+                },
+                "type": {
+                    "description": "按权限类型筛选：menu、button",
+                    "type": "string",
+                    "example": "menu"
+                }
+            }
+        },
+        "controllers.GetPermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Permission"
+                    }
+                },
+                "next_cursor": {
+                    "description": "游标分页的下一页游标，为空表示没有更多数据",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "游标分页时不返回",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.GetRecycleBinRequest": {
+            "type": "object",
+            "required": [
+                "pageSize",
+                "type"
+            ],
+            "properties": {
+                "created_from": {
+                    "description": "创建时间起（含）",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00+08:00"
+                },
+                "created_to": {
+                    "description": "创建时间止（不含）",
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00+08:00"
+                },
+                "cursor": {
+                    "description": "游标分页的游标，游标分页不返回总数",
+                    "type": "string",
+                    "example": ""
+                },
+                "keyword": {
+                    "description": "关键字，模糊匹配名称、编码等字段",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "page": {
+                    "description": "页码分页的页码，默认第一页",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "pageSize": {
+                    "description": "每页条数，最大500",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "sort": {
+                    "description": "排序字段，\"-\" 前缀表示倒序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "-created_at"
+                    ]
+                },
+                "type": {
+                    "description": "资源类型：user、role、permission、menu、button",
+                    "type": "string",
+                    "example": "role"
+                }
+            }
+        },
+        "controllers.GetRecycleBinResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.RecycleBinItem"
+                    }
+                },
+                "next_cursor": {
+                    "description": "游标分页的下一页游标，为空表示没有更多数据",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "游标分页时不返回",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.GetRolesRequest": {
+            "type": "object",
+            "required": [
+                "pageSize"
+            ],
+            "properties": {
+                "code": {
+                    "description": "按角色编码精确筛选",
+                    "type": "string",
+                    "example": "ROLE_ADMIN"
+                },
+                "created_from": {
+                    "description": "创建时间起（含）",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00+08:00"
+                },
+                "created_to": {
+                    "description": "创建时间止（不含）",
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00+08:00"
+                },
+                "cursor": {
+                    "description": "游标分页的游标，游标分页不返回总数",
+                    "type": "string",
+                    "example": ""
+                },
+                "keyword": {
+                    "description": "关键字，模糊匹配名称、编码等字段",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "page": {
+                    "description": "页码分页的页码，默认第一页",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "pageSize": {
+                    "description": "每页条数，最大500",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "permission_id": {
+                    "description": "按拥有的权限筛选",
+                    "type": "integer",
+                    "example": 1
+                },
+                "sort": {
+                    "description": "排序字段，\"-\" 前缀表示倒序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "-created_at"
+                    ]
+                },
+                "user_id": {
+                    "description": "按绑定的用户筛选",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "controllers.GetRolesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "next_cursor": {
+                    "description": "游标分页的下一页游标，为空表示没有更多数据",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "游标分页时不返回",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.GetTenantsRequest": {
+            "type": "object",
+            "required": [
+                "pageSize"
+            ],
+            "properties": {
+                "created_from": {
+                    "description": "创建时间起（含）",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00+08:00"
+                },
+                "created_to": {
+                    "description": "创建时间止（不含）",
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00+08:00"
+                },
+                "cursor": {
+                    "description": "游标分页的游标，游标分页不返回总数",
+                    "type": "string",
+                    "example": ""
+                },
+                "keyword": {
+                    "description": "关键字，模糊匹配名称、编码等字段",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "page": {
+                    "description": "页码分页的页码，默认第一页",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "pageSize": {
+                    "description": "每页条数，最大500",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "sort": {
+                    "description": "排序字段，\"-\" 前缀表示倒序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "-created_at"
+                    ]
+                },
+                "status": {
+                    "description": "按租户状态筛选",
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "controllers.GetTenantsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tenant"
+                    }
+                },
+                "next_cursor": {
+                    "description": "游标分页的下一页游标，为空表示没有更多数据",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "游标分页时不返回",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.GetUsersRequest": {
+            "type": "object",
+            "required": [
+                "pageSize"
+            ],
+            "properties": {
+                "created_from": {
+                    "description": "创建时间起（含）",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00+08:00"
+                },
+                "created_to": {
+                    "description": "创建时间止（不含）",
+                    "type": "string",
+                    "example": "2024-02-01T00:00:00+08:00"
+                },
+                "cursor": {
+                    "description": "游标分页的游标，游标分页不返回总数",
+                    "type": "string",
+                    "example": ""
+                },
+                "keyword": {
+                    "description": "关键字，模糊匹配名称、编码等字段",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "page": {
+                    "description": "页码分页的页码，默认第一页",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "pageSize": {
+                    "description": "每页条数，最大500",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "role_id": {
+                    "description": "按角色筛选",
+                    "type": "integer",
+                    "example": 1
+                },
+                "sort": {
+                    "description": "排序字段，\"-\" 前缀表示倒序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "-created_at"
+                    ]
+                }
+            }
+        },
+        "controllers.GetUsersResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                },
+                "next_cursor": {
+                    "description": "游标分页的下一页游标，为空表示没有更多数据",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "游标分页时不返回",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "description": "密码",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "username": {
+                    "description": "用户名",
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "controllers.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "访问令牌有效期，单位秒",
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "description": "刷新令牌，每次刷新后轮换",
+                    "type": "string",
+                    "example": "q1Xv0n..."
+                },
+                "token": {
+                    "description": "JWT访问令牌",
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "token_type": {
+                    "description": "令牌类型",
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "controllers.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "刷新令牌，传入时一并吊销其所在的令牌族",
+                    "type": "string",
+                    "example": "q1Xv0n..."
+                }
+            }
+        },
+        "controllers.MoveMenuRequest": {
+            "type": "object",
+            "properties": {
+                "parent_id": {
+                    "description": "目标上级菜单ID，0 表示根级",
+                    "type": "integer",
+                    "example": 0
+                },
+                "position": {
+                    "description": "在目标同级菜单中的位置，从 0 开始，不传时放在最后",
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "controllers.PatchPermissionsRequest": {
+            "type": "object",
+            "properties": {
+                "allow": {
+                    "description": "授予的权限ID，已拒绝的改为授予",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "deny": {
+                    "description": "拒绝的权限ID，已授予的改为拒绝",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3
+                    ]
+                },
+                "remove": {
+                    "description": "解除绑定的权限ID，未绑定的忽略",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        4
+                    ]
+                }
+            }
+        },
+        "controllers.PatchPermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "description": "修改后角色的全部权限绑定",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.RolePermissionBinding"
+                    }
+                }
+            }
+        },
+        "controllers.PatchRolesRequest": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "description": "添加的角色ID，已绑定的忽略",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "remove": {
+                    "description": "移除的角色ID，未绑定的忽略",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3
+                    ]
+                }
+            }
+        },
+        "controllers.PatchRolesResponse": {
+            "type": "object",
+            "properties": {
+                "role_ids": {
+                    "description": "修改后用户的全部角色ID",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "controllers.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "description": "刷新令牌",
+                    "type": "string",
+                    "example": "q1Xv0n..."
+                }
+            }
+        },
+        "controllers.UpdateButtonRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "按钮编码",
+                    "type": "string",
+                    "example": "edit"
+                },
+                "menu_id": {
+                    "description": "所属菜单ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "按钮名称",
+                    "type": "string",
+                    "example": "编辑"
+                }
+            }
+        },
+        "controllers.UpdateButtonResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "按钮信息更新成功"
+                }
+            }
+        },
+        "controllers.UpdateMenuRequest": {
+            "type": "object",
+            "properties": {
+                "icon": {
+                    "description": "菜单图标",
+                    "type": "string",
+                    "example": "user"
+                },
+                "name": {
+                    "description": "菜单名称",
+                    "type": "string",
+                    "example": "用户管理"
+                },
+                "order": {
+                    "description": "排序序号",
+                    "type": "integer",
+                    "example": 2
+                },
+                "parent_id": {
+                    "description": "父级菜单ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "path": {
+                    "description": "菜单路径",
+                    "type": "string",
+                    "example": "/user"
+                }
+            }
+        },
+        "controllers.UpdateMenuResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "菜单信息更新成功"
+                }
+            }
+        },
+        "controllers.UpdatePermissionRequest": {
+            "type": "object",
+            "properties": {
+                "button_id": {
+                    "description": "按钮ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "code": {
+                    "description": "权限编码",
+                    "type": "string",
+                    "example": "user:create"
+                },
+                "menu_id": {
+                    "description": "菜单ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "权限名称",
+                    "type": "string",
+                    "example": "创建用户"
+                },
+                "parent_id": {
+                    "description": "父级权限ID，为空表示顶级权限",
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "description": "权限类型",
+                    "type": "string",
+                    "example": "menu"
+                }
+            }
+        },
+        "controllers.UpdatePermissionResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "权限信息更新成功"
+                }
+            }
+        },
+        "controllers.UpdateTenantRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "租户名称",
+                    "type": "string",
+                    "example": "ACME 公司"
+                },
+                "status": {
+                    "description": "租户状态：active、disabled",
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "controllers.UpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "新密码",
+                    "type": "string",
+                    "example": "654321"
+                },
+                "username": {
+                    "description": "用户名",
+                    "type": "string",
+                    "example": "editor"
+                }
+            }
+        },
+        "controllers.UpdateUserResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "响应消息",
+                    "type": "string",
+                    "example": "用户信息更新成功"
+                }
+            }
+        },
+        "keyring.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "OKP曲线",
+                    "type": "string"
+                },
+                "e": {
+                    "description": "RSA公钥指数",
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA模数",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "description": "OKP公钥",
+                    "type": "string"
+                }
+            }
+        },
+        "keyring.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/keyring.JWK"
+                    }
+                }
+            }
+        },
+        "models.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "actor_name": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "client_ip": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "9f2c4e0a7b1d4c3e8a6f5b2d1c0e9f8a"
+                },
+                "resource_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "resource_type": {
+                    "type": "string",
+                    "example": "role"
+                },
+                "tenant_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.Button": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "create"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "menu_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "创建用户"
+                },
+                "permission_code": {
+                    "type": "string",
+                    "example": "user:create"
+                },
+                "tenant_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "乐观锁版本",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.Menu": {
+            "type": "object",
+            "properties": {
+                "button_association": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "component": {
+                    "type": "string",
+                    "example": "@/views/system/index"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "setting"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "is_visible": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "meta": {
+                    "$ref": "#/definitions/models.MenuMeta"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "系统管理"
+                },
+                "order": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "parent_id": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/system"
+                },
+                "tenant_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "乐观锁版本，修改和移动时加一",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.MenuMeta": {
+            "type": "object",
+            "properties": {
+                "icon": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Permission": {
+            "type": "object",
+            "properties": {
+                "button_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "code": {
+                    "description": "未删除的记录中租户内唯一",
+                    "type": "string",
+                    "example": "user:create"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "menu_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "创建用户"
+                },
+                "parent_id": {
+                    "description": "父级权限ID",
+                    "type": "integer",
+                    "example": 0
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "tenant_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "type": "string",
+                    "example": "menu"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "乐观锁版本",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.Role": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "未删除的记录中租户内唯一",
+                    "type": "string",
+                    "example": "ROLE_ADMIN"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "系统管理员角色"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "管理员"
+                },
+                "parents": {
+                    "description": "继承的父角色，角色拥有父角色的全部权限",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Permission"
+                    }
+                },
+                "tenant_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                },
+                "version": {
+                    "description": "乐观锁版本，角色信息、权限绑定或父角色变更时加一",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.Tenant": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "acme"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ACME 公司"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "tenant_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "description": "未删除的用户中全局唯一，登录时据此确定所属租户",
+                    "type": "string",
+                    "example": "admin"
+                },
+                "version": {
+                    "description": "乐观锁版本，修改信息或角色绑定时加一",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "services.AuthzResult": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "permission": {
+                    "type": "string",
+                    "example": "user:create"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "super_admin",
+                        "granted",
+                        "denied",
+                        "not_granted"
+                    ],
+                    "example": "granted"
+                },
+                "resource": {
+                    "type": "string",
+                    "example": "order:1024"
+                },
+                "role_id": {
+                    "description": "决定结果的角色：授予或拒绝该权限的用户角色，或超级管理员角色",
+                    "type": "integer",
+                    "example": 2
+                },
+                "subject": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "tenant_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "services.BindPermissionsResult": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "description": "替换后角色的全部权限绑定",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.RolePermissionBinding"
+                    }
+                },
+                "unknown": {
+                    "description": "未找到对应权限而被忽略的条目",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "services.ExplainPath": {
+            "type": "object",
+            "properties": {
+                "effect": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "example": "allow"
+                },
+                "outcome": {
+                    "type": "string",
+                    "enum": [
+                        "applied",
+                        "overridden"
+                    ],
+                    "example": "applied"
+                },
+                "permissions": {
+                    "description": "从绑定的权限到匹配目标的权限",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ExplainPermission"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "角色绑定成功",
-                        "schema": {
-                            "type": "object"
-                        }
-                    },
-                    "400": {
-                        "description": "无效的请求参数",
-                        "schema": {
-                            "type": "object"
-                        }
-                    },
-                    "500": {
-                        "description": "绑定角色失败",
-                        "schema": {
-                            "type": "object"
-                        }
+                },
+                "roles": {
+                    "description": "从用户直接绑定的角色到持有绑定的角色",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ExplainRole"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Button": {
+        },
+        "services.ExplainPermission": {
             "type": "object",
             "properties": {
-                "action": {
-                    "type": "string",
-                    "example": "create"
+                "button_id": {
+                    "type": "integer"
                 },
                 "code": {
                     "type": "string",
-                    "example": "create"
-                },
-                "created_at": {
-                    "type": "string"
+                    "example": "user:*"
                 },
                 "id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 5
                 },
                 "menu_id": {
                     "type": "integer",
@@ -1294,24 +5302,84 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string",
-                    "example": "创建用户"
+                    "example": "用户管理"
                 },
-                "permission_code": {
+                "relation": {
+                    "description": "与上一项权限的关系",
                     "type": "string",
-                    "example": "user:create"
+                    "enum": [
+                        "bound",
+                        "wildcard",
+                        "child",
+                        "parent"
+                    ],
+                    "example": "bound"
                 },
-                "updated_at": {
-                    "type": "string"
+                "type": {
+                    "type": "string",
+                    "example": "menu"
                 }
             }
         },
-        "models.Menu": {
+        "services.ExplainRole": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "ROLE_MANAGER"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "经理"
+                }
+            }
+        },
+        "services.Explanation": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "paths": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ExplainPath"
+                    }
+                },
+                "reason": {
+                    "description": "同 Decision.Reason",
+                    "type": "string",
+                    "example": "granted"
+                },
+                "role_id": {
+                    "description": "决定结果的用户角色",
+                    "type": "integer",
+                    "example": 2
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "services.MenuTreeNode": {
             "type": "object",
             "properties": {
                 "button_association": {
                     "type": "boolean",
                     "example": false
                 },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.MenuTreeNode"
+                    }
+                },
                 "component": {
                     "type": "string",
                     "example": "@/views/system/index"
@@ -1350,73 +5418,74 @@ const docTemplate = `{
                     "type": "string",
                     "example": "/system"
                 },
+                "tenant_id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "updated_at": {
                     "type": "string"
-                }
-            }
-        },
-        "models.MenuMeta": {
-            "type": "object",
-            "properties": {
-                "icon": {
-                    "type": "string"
                 },
-                "title": {
-                    "type": "string"
+                "version": {
+                    "description": "乐观锁版本，修改和移动时加一",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "models.Permission": {
+        "services.PermissionTreeNode": {
             "type": "object",
             "properties": {
-                "button_id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "created_at": {
-                    "type": "string"
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.PermissionTreeNode"
+                    }
                 },
-                "id": {
-                    "type": "integer",
-                    "example": 1
+                "enable": {
+                    "description": "角色是否拥有该权限，即 state 为 allowed",
+                    "type": "boolean"
                 },
-                "menu_id": {
-                    "type": "integer",
-                    "example": 1
+                "half_checked": {
+                    "description": "子孙权限中只有一部分被授予",
+                    "type": "boolean"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "查看仪表盘"
+                "icon": {
+                    "type": "string"
                 },
-                "permission_code": {
-                    "type": "string",
-                    "example": "view_dashboard"
+                "id": {
+                    "type": "integer"
                 },
-                "roles": {
+                "inherited_from": {
+                    "description": "当前状态来自这些祖先角色的绑定",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Role"
+                        "type": "integer"
                     }
                 },
-                "type": {
-                    "type": "string",
-                    "example": "menu"
-                },
-                "updated_at": {
+                "name": {
                     "type": "string"
+                },
+                "own": {
+                    "description": "当前状态来自角色自身的绑定",
+                    "type": "boolean"
+                },
+                "state": {
+                    "description": "授权状态，拒绝优先于授予",
+                    "type": "string",
+                    "enum": [
+                        "allowed",
+                        "denied",
+                        "unset"
+                    ]
                 }
             }
         },
-        "models.Role": {
+        "services.RecycleBinItem": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "example": "系统管理员角色"
-                },
                 "id": {
                     "type": "integer",
                     "example": 1
@@ -1425,49 +5494,88 @@ const docTemplate = `{
                     "type": "string",
                     "example": "管理员"
                 },
-                "permissions": {
+                "type": {
+                    "type": "string",
+                    "example": "role"
+                }
+            }
+        },
+        "services.RoleEffectivePermissions": {
+            "type": "object",
+            "properties": {
+                "ancestors": {
+                    "description": "继承的全部祖先角色，近的在前",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Permission"
+                        "$ref": "#/definitions/models.Role"
                     }
                 },
-                "updated_at": {
-                    "type": "string"
+                "parents": {
+                    "description": "直接继承的父角色",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
                 },
-                "users": {
+                "permissions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.User"
+                        "$ref": "#/definitions/services.RolePermissionGrant"
                     }
                 }
             }
         },
-        "models.User": {
+        "services.RolePermissionBinding": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "effect": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "example": "allow"
                 },
-                "id": {
+                "permission_id": {
                     "type": "integer",
                     "example": 1
-                },
-                "password": {
+                }
+            }
+        },
+        "services.RolePermissionGrant": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "user:create"
                 },
-                "roles": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "inherited_from": {
+                    "description": "当前状态来自这些祖先角色的绑定",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Role"
+                        "type": "integer"
                     }
                 },
-                "updated_at": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "创建用户"
                 },
-                "username": {
+                "own": {
+                    "description": "当前状态来自角色自身的绑定",
+                    "type": "boolean",
+                    "example": true
+                },
+                "state": {
                     "type": "string",
-                    "example": "admin"
+                    "enum": [
+                        "allowed",
+                        "denied"
+                    ],
+                    "example": "allowed"
                 }
             }
         },
@@ -1519,6 +5627,32 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "services.UserPermissions": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "description": "按编码排序，通配权限已展开为具体编码",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user:create",
+                        "user:delete"
+                    ]
+                },
+                "super_admin": {
+                    "description": "超级管理员不受权限限制，前端应放行全部按钮",
+                    "type": "boolean",
+                    "example": false
+                },
+                "version": {
+                    "description": "权限编码的版本，与响应头 ETag 一致，编码不变时版本不变",
+                    "type": "string",
+                    "example": "9f86d081884c7d65"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,193 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"os"
+	"sort"
+	"tenant-center/config"
+	"time"
+)
+
+// 错误定义
+var (
+	ErrNoSigningKey = errors.New("没有可用的签名密钥")
+	ErrUnknownKey   = errors.New("未知的签名密钥")
+)
+
+// Key 签名密钥
+type Key struct {
+	ID        string            // JWT头部的 kid
+	Method    jwt.SigningMethod // RS256 或 EdDSA
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+	NotBefore time.Time // 开始用于签名的时间，零值表示立即可用
+	NotAfter  time.Time // 停止用于签名的时间，零值表示不限
+}
+
+// Keyring 签名密钥环，支持多把密钥并存和按时间自动轮换
+//
+// 签名时使用当前生效且 NotBefore 最晚的密钥；密钥停止签名后仍会在一个访问令牌有效期内
+// 继续用于校验，保证轮换前签发的令牌不会提前失效。尚未生效的密钥会提前在JWKS中公布，
+// 下游服务可以在轮换前缓存新公钥。
+type Keyring struct {
+	keys        []*Key
+	verifyGrace time.Duration
+}
+
+// New 根据配置加载密钥环，未配置密钥时生成一把仅在本进程内有效的临时Ed25519密钥
+func New(cfg config.JWTConfig) (*Keyring, error) {
+	ring := &Keyring{verifyGrace: cfg.Expire.Duration}
+
+	for _, kc := range cfg.Keys {
+		key, err := loadKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("加载签名密钥 %s 失败: %w", kc.ID, err)
+		}
+		ring.keys = append(ring.keys, key)
+	}
+
+	if len(ring.keys) == 0 {
+		key, err := GenerateEd25519("ephemeral")
+		if err != nil {
+			return nil, err
+		}
+		ring.keys = append(ring.keys, key)
+	}
+
+	// 按生效时间排序，便于选择最新的签名密钥
+	sort.SliceStable(ring.keys, func(i, j int) bool {
+		return ring.keys[i].NotBefore.Before(ring.keys[j].NotBefore)
+	})
+	return ring, nil
+}
+
+// GenerateEd25519 生成随机的Ed25519密钥
+func GenerateEd25519(kid string) (*Key, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, Private: priv, Public: pub}, nil
+}
+
+// loadKey 读取PEM格式（PKCS#8或PKCS#1）的私钥文件
+func loadKey(kc config.SigningKeyConfig) (*Key, error) {
+	data, err := os.ReadFile(kc.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("无效的PEM文件")
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{ID: kc.ID, NotBefore: kc.NotBefore, NotAfter: kc.NotAfter}
+	switch priv := parsed.(type) {
+	case *rsa.PrivateKey:
+		if priv.N.BitLen() < 2048 {
+			return nil, errors.New("RSA密钥长度不能小于2048位")
+		}
+		key.Method = jwt.SigningMethodRS256
+		key.Private = priv
+		key.Public = &priv.PublicKey
+	case ed25519.PrivateKey:
+		key.Method = jwt.SigningMethodEdDSA
+		key.Private = priv
+		key.Public = priv.Public()
+	default:
+		return nil, errors.New("仅支持RSA和Ed25519私钥")
+	}
+	return key, nil
+}
+
+// SigningKey 返回当前用于签名的密钥
+func (r *Keyring) SigningKey(now time.Time) (*Key, error) {
+	var current *Key
+	for _, key := range r.keys {
+		if !key.NotBefore.IsZero() && now.Before(key.NotBefore) {
+			continue
+		}
+		if !key.NotAfter.IsZero() && !now.Before(key.NotAfter) {
+			continue
+		}
+		// keys 已按 NotBefore 升序排列，取最后一把生效的密钥
+		current = key
+	}
+	if current == nil {
+		return nil, ErrNoSigningKey
+	}
+	return current, nil
+}
+
+// VerificationKey 根据 kid 返回校验用的公钥
+func (r *Keyring) VerificationKey(kid string, now time.Time) (*Key, error) {
+	for _, key := range r.keys {
+		if key.ID != kid {
+			continue
+		}
+		if !key.NotAfter.IsZero() && now.After(key.NotAfter.Add(r.verifyGrace)) {
+			return nil, ErrUnknownKey
+		}
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// Sign 使用当前签名密钥签发令牌，并在头部写入 kid
+func (r *Keyring) Sign(claims jwt.Claims) (string, error) {
+	key, err := r.SigningKey(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// Keyfunc 供 jwt.Parse 使用的公钥查找函数，按头部的 kid 选择密钥并校验签名算法
+func (r *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, err := r.VerificationKey(kid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("无效的token签名算法")
+	}
+	return key.Public, nil
+}
+
+// ValidMethods 返回密钥环中使用的签名算法，用于限制可接受的算法
+func (r *Keyring) ValidMethods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range r.keys {
+		alg := key.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
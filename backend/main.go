@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"log"
+	"os"
+	"strings"
+	"tenant-center/config"
 	_ "tenant-center/docs"
+	"tenant-center/keyring"
+	"tenant-center/models"
 	"tenant-center/routes"
+	"tenant-center/services"
 )
 
 // @title 租户中心API
@@ -19,20 +26,109 @@ import (
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
+//
+// 第一个参数不以 - 开头时作为运维命令执行后退出：
+//   - rebuild-permissions：全量重建生效权限投影
+//   - check-permissions：对比生效权限投影与实时计算的结果，存在不一致时以状态 1 退出
 func main() {
-	dsn := "root:password@tcp(ipaddress:3306)/tenant_v1?charset=utf8mb4&parseTime=True&loc=Local"
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	command, args := "", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	if command != "" && command != "rebuild-permissions" && command != "check-permissions" {
+		log.Fatal("Unknown command: ", command)
+	}
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	gin.SetMode(cfg.Server.Mode)
+
+	db, err := gorm.Open(mysql.Open(cfg.Database.DSN), &gorm.Config{})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	// 配置连接池
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("Failed to get database handle:", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime.Duration)
+
+	// 注册租户隔离插件
+	if err := db.Use(models.TenantScope{}); err != nil {
+		log.Fatal("Failed to register tenant scope:", err)
+	}
+
+	if cfg.Database.AutoMigrate {
+		if err := models.AutoMigrate(db); err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+	}
+
+	projection := services.NewPermissionProjectionService(db, cfg)
+	switch command {
+	case "rebuild-permissions":
+		if err := projection.Rebuild(context.Background()); err != nil {
+			log.Fatal("Failed to rebuild effective permissions:", err)
+		}
+		log.Println("生效权限投影已重建")
+		return
+
+	case "check-permissions":
+		mismatches, err := projection.Check(context.Background())
+		if err != nil {
+			log.Fatal("Failed to check effective permissions:", err)
+		}
+		for _, m := range mismatches {
+			log.Printf("租户 %d 用户 %d 的生效权限不一致，缺少 %v，多出 %v", m.TenantID, m.UserID, m.Missing, m.Unexpected)
+		}
+		if len(mismatches) > 0 {
+			log.Fatalf("%d 个用户的生效权限与实时计算结果不一致，可执行 rebuild-permissions 重建", len(mismatches))
+		}
+		log.Println("生效权限投影与实时计算结果一致")
+		return
+	}
+
+	// 升级后首次启动时投影表为空，先全量重建
+	rebuilt, err := projection.RebuildIfEmpty(context.Background())
+	if err != nil {
+		log.Fatal("Failed to rebuild effective permissions:", err)
+	}
+	if rebuilt {
+		log.Println("生效权限投影为空，已全量重建")
+	}
+
+	// 进程内权限缓存，通过轮询策略版本得知其他实例的修改，需在创建服务之前注册
+	if cfg.Authz.CachePollInterval.Duration > 0 {
+		cache := services.NewPolicyCache(cfg)
+		if err := db.Use(cache); err != nil {
+			log.Fatal("Failed to register policy cache:", err)
+		}
+		go cache.Watch(context.Background())
+	}
+
+	// 加载JWT签名密钥
+	ring, err := keyring.New(cfg.JWT)
+	if err != nil {
+		log.Fatal("Failed to load signing keys:", err)
+	}
+	if len(cfg.JWT.Keys) == 0 {
+		log.Println("未配置 jwt.keys，使用临时签名密钥，重启后已签发的令牌将失效")
+	}
+
 	r := gin.Default()
 
 	// 初始化路由
-	routes.SetupRoutes(r, db)
+	routes.SetupRoutes(r, db, cfg, ring)
 
 	// 添加swagger文档路由
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	r.Run(":8080")
+	r.Run(cfg.Server.Addr())
 }
@@ -3,13 +3,14 @@ package middleware
 import (
 	"errors"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"net/http"
 	"strings"
+	"tenant-center/reqctx"
+	"tenant-center/services"
 )
 
-// JWTAuth JWT认证中间件
-func JWTAuth() gin.HandlerFunc {
+// JWTAuth JWT认证中间件，校验访问令牌的签名、有效期和吊销状态
+func JWTAuth(tokenService *services.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 从请求头中获取token
 		authHeader := c.GetHeader("Authorization")
@@ -28,31 +29,27 @@ func JWTAuth() gin.HandlerFunc {
 		}
 
 		// 解析token
-		tokenString := parts[1]
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// 验证签名算法
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("无效的token签名算法")
-			}
-			return []byte("your-secret-key"), nil // 注意：在实际应用中，密钥应该从配置文件中读取
-		})
-
+		claims, err := tokenService.WithContext(c.Request.Context()).ParseAccessToken(parts[1])
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的token"})
+			if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrTokenRevoked) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "校验token失败"})
+			}
 			c.Abort()
 			return
 		}
 
-		// 验证token是否有效
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			// 将用户信息存储到上下文中
-			c.Set("user_id", int(claims["user_id"].(float64)))
-			c.Set("username", claims["username"].(string))
-			c.Next()
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的token"})
-			c.Abort()
-			return
-		}
+		// 将用户信息存储到上下文中
+		c.Set("user_id", claims.UserID)
+		c.Set("username", claims.Username)
+		c.Set("tenant_id", claims.TenantID)
+		c.Set("claims", claims)
+
+		// 租户ID写入请求context，供数据库查询自动隔离；操作人供审计日志使用
+		reqCtx := reqctx.WithTenantID(c.Request.Context(), claims.TenantID)
+		reqCtx = reqctx.WithActor(reqCtx, reqctx.Actor{UserID: claims.UserID, Username: claims.Username})
+		c.Request = c.Request.WithContext(reqCtx)
+		c.Next()
 	}
 }
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"tenant-center/services"
+)
+
+// Authorizer 权限校验中间件，需要在 JWTAuth 之后使用
+type Authorizer struct {
+	resolver *services.PermissionResolver
+}
+
+// NewAuthorizer 创建权限校验中间件实例
+func NewAuthorizer(resolver *services.PermissionResolver) *Authorizer {
+	return &Authorizer{resolver: resolver}
+}
+
+// RequirePermission 要求当前用户拥有指定权限编码，否则返回403，判定规则与 /api/authz/check 一致
+func (a *Authorizer) RequirePermission(code string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetInt("user_id")
+		if userID == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "未登录"})
+			c.Abort()
+			return
+		}
+
+		allowed, err := a.resolver.WithContext(c.Request.Context()).HasPermission(userID, code)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "权限校验失败"})
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "没有权限访问", "permission": code})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"tenant-center/reqctx"
+)
+
+// RequestIDHeader 请求ID的请求头和响应头
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID 请求ID中间件，沿用调用方传入的请求ID或生成新的请求ID，连同客户端IP写入请求context
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			b := make([]byte, 16)
+			_, _ = rand.Read(b)
+			requestID = hex.EncodeToString(b)
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(reqctx.WithRequestInfo(c.Request.Context(), reqctx.RequestInfo{
+			RequestID: requestID,
+			ClientIP:  c.ClientIP(),
+		}))
+		c.Next()
+	}
+}
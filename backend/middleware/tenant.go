@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"tenant-center/models"
+)
+
+// PlatformTenantOnly 仅允许默认（平台）租户的用户访问，用于租户管理等跨租户操作
+func PlatformTenantOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetInt("tenant_id") != models.DefaultTenantID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "仅平台租户可访问"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 审计操作类型
+const (
+	AuditActionCreate          = "create"
+	AuditActionUpdate          = "update"
+	AuditActionDelete          = "delete"
+	AuditActionRestore         = "restore"
+	AuditActionPurge           = "purge"
+	AuditActionBindRoles       = "bind_roles"
+	AuditActionBindPermissions = "bind_permissions"
+	AuditActionMove            = "move"
+	AuditActionBindParents     = "bind_parents"
+)
+
+// AuditLog 审计日志，每次管理操作在同一事务中写入一条
+type AuditLog struct {
+	ID           int             `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
+	TenantID     int             `gorm:"not null;default:1;index:idx_audit_tenant_created,priority:1" json:"tenant_id" example:"1"`
+	ActorID      int             `gorm:"not null;default:0;index" json:"actor_id" example:"1"`
+	ActorName    string          `gorm:"size:255" json:"actor_name" example:"admin"`
+	ResourceType string          `gorm:"size:32;not null;index:idx_audit_resource" json:"resource_type" example:"role"`
+	ResourceID   int             `gorm:"not null;index:idx_audit_resource" json:"resource_id" example:"1"`
+	Action       string          `gorm:"size:32;not null;index" json:"action" example:"update"`
+	Before       json.RawMessage `gorm:"column:before_data;type:json" json:"before,omitempty" swaggertype:"object"`
+	After        json.RawMessage `gorm:"column:after_data;type:json" json:"after,omitempty" swaggertype:"object"`
+	ClientIP     string          `gorm:"size:64" json:"client_ip" example:"127.0.0.1"`
+	RequestID    string          `gorm:"size:64;index" json:"request_id" example:"9f2c4e0a7b1d4c3e8a6f5b2d1c0e9f8a"`
+	CreatedAt    time.Time       `gorm:"default:CURRENT_TIMESTAMP;index:idx_audit_tenant_created,priority:2" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -1,18 +1,22 @@
 package models
 
 import (
+	"gorm.io/gorm"
 	"time"
 )
 
 // Button 按钮模型
 type Button struct {
-	ID             int       `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
-	Name           string    `gorm:"size:255;not null" json:"name" example:"创建用户"`
-	Action         string    `gorm:"size:255;not null" json:"action" example:"create"`
-	MenuID         int       `gorm:"not null" json:"menu_id" example:"1"`
-	PermissionCode string    `gorm:"size:255;not null" json:"permission_code" example:"user:create"`
-	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	ID             int            `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
+	TenantID       int            `gorm:"not null;default:1;index" json:"tenant_id" example:"1"`
+	Name           string         `gorm:"size:255;not null" json:"name" example:"创建用户"`
+	Action         string         `gorm:"size:255;not null" json:"action" example:"create"`
+	MenuID         int            `gorm:"not null" json:"menu_id" example:"1"`
+	PermissionCode string         `gorm:"size:255;not null" json:"permission_code" example:"user:create"`
+	Version        int            `gorm:"not null;default:1" json:"version" example:"1"` // 乐观锁版本
+	CreatedAt      time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName 指定表名
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"gorm.io/gorm"
 	"time"
 )
 
@@ -29,21 +30,24 @@ func (m *MenuMeta) Scan(value interface{}) error {
 
 // Menu 菜单模型
 type Menu struct {
-	ID                int       `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
-	ParentID          *int      `gorm:"default:0" json:"parent_id" example:"0"`
-	Name              string    `gorm:"size:255;not null" json:"name" example:"系统管理"`
-	Path              string    `gorm:"size:255;not null" json:"path" example:"/system"`
-	Component         string    `gorm:"size:255;not null" json:"component" example:"@/views/system/index"`
-	Icon              string    `gorm:"size:255" json:"icon,omitempty" example:"setting"`
-	Order             int       `gorm:"default:0" json:"order" example:"1"`
-	Meta              MenuMeta  `gorm:"type:json" json:"meta,omitempty"`
-	IsVisible         bool      `gorm:"default:true" json:"is_visible" example:"true"`
-	ButtonAssociation bool      `gorm:"default:false" json:"button_association" example:"false"`
-	CreatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	ID                int            `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
+	TenantID          int            `gorm:"not null;default:1;index" json:"tenant_id" example:"1"`
+	ParentID          *int           `gorm:"default:0" json:"parent_id" example:"0"`
+	Name              string         `gorm:"size:255;not null" json:"name" example:"系统管理"`
+	Path              string         `gorm:"size:255;not null" json:"path" example:"/system"`
+	Component         string         `gorm:"size:255;not null" json:"component" example:"@/views/system/index"`
+	Icon              string         `gorm:"size:255" json:"icon,omitempty" example:"setting"`
+	Order             int            `gorm:"default:0" json:"order" example:"1"`
+	Meta              MenuMeta       `gorm:"type:json" json:"meta,omitempty"`
+	IsVisible         bool           `gorm:"default:true" json:"is_visible" example:"true"`
+	ButtonAssociation bool           `gorm:"default:false" json:"button_association" example:"false"`
+	Version           int            `gorm:"not null;default:1" json:"version" example:"1"` // 乐观锁版本，修改和移动时加一
+	CreatedAt         time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName 指定表名
 func (Menu) TableName() string {
 	return "menu"
-}
\ No newline at end of file
+}
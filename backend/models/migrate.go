@@ -0,0 +1,50 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// AutoMigrate 同步数据表结构并初始化默认租户
+func AutoMigrate(db *gorm.DB) error {
+	// role_permission 带有授权效果列，需要在迁移前登记自定义关联表
+	if err := db.SetupJoinTable(&Role{}, "Permissions", &RolePermission{}); err != nil {
+		return err
+	}
+	if err := db.SetupJoinTable(&Permission{}, "Roles", &RolePermission{}); err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(&Tenant{}, &User{}, &Role{}, &Permission{}, &Menu{}, &Button{}, &RefreshToken{}, &RevokedToken{}, &DeletedBinding{}, &AuditLog{}, &RolePermission{}, &UserEffectivePermission{}, &PolicyVersion{}); err != nil {
+		return err
+	}
+
+	// 角色、权限编码由全局唯一改为租户内唯一，之后用户名、角色编码、权限编码又改为只在未删除的记录中唯一，移除旧的唯一索引
+	migrator := db.Migrator()
+	staleIndexes := []struct {
+		model interface{}
+		name  string
+	}{
+		{&User{}, "username"},
+		{&Role{}, "code"},
+		{&Role{}, "idx_role_tenant_code"},
+		{&Permission{}, "code"},
+		{&Permission{}, "idx_permission_tenant_code"},
+	}
+	for _, index := range staleIndexes {
+		if migrator.HasIndex(index.model, index.name) {
+			if err := migrator.DropIndex(index.model, index.name); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 存量数据的 tenant_id 默认为1，确保对应的默认租户存在
+	tenant := Tenant{ID: DefaultTenantID, Code: "default", Name: "默认租户", Status: TenantStatusActive}
+	if err := db.Where(Tenant{ID: DefaultTenantID}).FirstOrCreate(&tenant).Error; err != nil {
+		return err
+	}
+
+	// 策略版本只有一行，修改授权数据时原地递增
+	policy := PolicyVersion{ID: PolicyVersionID}
+	return db.Where(PolicyVersion{ID: PolicyVersionID}).FirstOrCreate(&policy).Error
+}
@@ -1,21 +1,26 @@
 package models
 
 import (
+	"gorm.io/gorm"
 	"time"
 )
 
 // Permission 权限模型
 type Permission struct {
-	ID        int       `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
-	Code      string    `gorm:"size:255;not null;unique" json:"code" example:"user:create"`
-	Name      string    `gorm:"size:255;not null" json:"name" example:"创建用户"`
-	Type      string    `gorm:"type:enum('menu','button');not null" json:"type" example:"menu"`
-	MenuID    *int      `gorm:"default:null" json:"menu_id,omitempty" example:"1"`
-	ButtonID  *int      `gorm:"default:null" json:"button_id,omitempty" example:"1"`
-	ParentID  *int      `gorm:"default:null" json:"parent_id,omitempty" example:"0"` // 父级权限ID
-	Roles     []Role    `gorm:"many2many:role_permission;" json:"roles,omitempty"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	ID        int            `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
+	TenantID  int            `gorm:"not null;default:1;uniqueIndex:idx_permission_tenant_code_alive" json:"tenant_id" example:"1"`
+	Code      string         `gorm:"size:255;not null;uniqueIndex:idx_permission_tenant_code_alive" json:"code" example:"user:create"` // 未删除的记录中租户内唯一
+	Name      string         `gorm:"size:255;not null" json:"name" example:"创建用户"`
+	Type      string         `gorm:"type:enum('menu','button');not null" json:"type" example:"menu"`
+	MenuID    *int           `gorm:"default:null" json:"menu_id,omitempty" example:"1"`
+	ButtonID  *int           `gorm:"default:null" json:"button_id,omitempty" example:"1"`
+	ParentID  *int           `gorm:"default:null" json:"parent_id,omitempty" example:"0"` // 父级权限ID
+	Roles     []Role         `gorm:"many2many:role_permission;" json:"roles,omitempty"`
+	Version   int            `gorm:"not null;default:1" json:"version" example:"1"` // 乐观锁版本
+	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Alive     *bool          `gorm:"->;type:tinyint(1) GENERATED ALWAYS AS (IF(deleted_at IS NULL, 1, NULL)) STORED;uniqueIndex:idx_permission_tenant_code_alive" json:"-"` // 未删除时为1、删除后为NULL，使回收站中的记录不占用编码
 }
 
 // TableName 指定表名
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// PolicyVersionID 策略版本表中唯一一行的ID
+const PolicyVersionID = 1
+
+// PolicyVersion 全局授权策略版本，只有一行
+//
+// 角色、权限、菜单、按钮及用户角色等授权数据在修改它们的事务中将版本加一，
+// 各实例定期轮询该版本，发现变化时清空进程内的权限缓存，无需额外的消息中间件。
+type PolicyVersion struct {
+	ID        int       `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Version   int64     `gorm:"not null;default:0" json:"version"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (PolicyVersion) TableName() string {
+	return "policy_version"
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// DeletedBinding 软删除时摘下的关联关系（user_role、role_permission、role_inheritance）
+//
+// 用户、角色、权限进入回收站时，其关联行会从关联表中移除并保存在这里，
+// 恢复时重新写回关联表，彻底删除时一并清理。ResourceType/ResourceID 为被删除的一方。
+type DeletedBinding struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID     int       `gorm:"not null;default:1;index" json:"tenant_id"`
+	ResourceType string    `gorm:"size:32;not null;index:idx_deleted_binding_resource" json:"resource_type"`
+	ResourceID   int       `gorm:"not null;index:idx_deleted_binding_resource" json:"resource_id"`
+	JoinTable    string    `gorm:"size:32;not null" json:"join_table"`
+	LeftID       int       `gorm:"not null" json:"left_id"`        // user_role.user_id 或 role_permission.role_id
+	RightID      int       `gorm:"not null" json:"right_id"`       // user_role.role_id 或 role_permission.permission_id
+	Effect       string    `gorm:"size:8" json:"effect,omitempty"` // role_permission 的授权效果，恢复时原样写回
+	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName 指定表名
+func (DeletedBinding) TableName() string {
+	return "deleted_binding"
+}
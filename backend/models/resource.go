@@ -0,0 +1,11 @@
+package models
+
+// 资源类型，用于回收站和审计日志
+const (
+	ResourceUser       = "user"
+	ResourceRole       = "role"
+	ResourcePermission = "permission"
+	ResourceMenu       = "menu"
+	ResourceButton     = "button"
+	ResourceTenant     = "tenant"
+)
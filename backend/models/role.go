@@ -1,22 +1,28 @@
 package models
 
 import (
+	"gorm.io/gorm"
 	"time"
 )
 
 // Role 角色模型
 type Role struct {
-	ID          int          `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
-	Name        string       `gorm:"size:255;not null" json:"name" example:"管理员"`
-	Code        string       `gorm:"size:255;not null;unique" json:"code" example:"ROLE_ADMIN"`
-	Description string       `gorm:"type:text" json:"description" example:"系统管理员角色"`
-	Permissions []Permission `gorm:"many2many:role_permission;" json:"permissions,omitempty"`
-	Users       []User       `gorm:"many2many:user_role;" json:"users,omitempty"`
-	CreatedAt   time.Time    `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time    `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	ID          int            `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
+	TenantID    int            `gorm:"not null;default:1;uniqueIndex:idx_role_tenant_code_alive" json:"tenant_id" example:"1"`
+	Name        string         `gorm:"size:255;not null" json:"name" example:"管理员"`
+	Code        string         `gorm:"size:255;not null;uniqueIndex:idx_role_tenant_code_alive" json:"code" example:"ROLE_ADMIN"` // 未删除的记录中租户内唯一
+	Description string         `gorm:"type:text" json:"description" example:"系统管理员角色"`
+	Permissions []Permission   `gorm:"many2many:role_permission;" json:"permissions,omitempty"`
+	Users       []User         `gorm:"many2many:user_role;" json:"users,omitempty"`
+	Parents     []Role         `gorm:"many2many:role_inheritance;joinForeignKey:RoleID;joinReferences:ParentRoleID" json:"parents,omitempty"` // 继承的父角色，角色拥有父角色的全部权限
+	Version     int            `gorm:"not null;default:1" json:"version" example:"1"`                                                         // 乐观锁版本，角色信息、权限绑定或父角色变更时加一
+	CreatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	Alive       *bool          `gorm:"->;type:tinyint(1) GENERATED ALWAYS AS (IF(deleted_at IS NULL, 1, NULL)) STORED;uniqueIndex:idx_role_tenant_code_alive" json:"-"` // 未删除时为1、删除后为NULL，使回收站中的记录不占用编码
 }
 
 // TableName 指定表名
 func (Role) TableName() string {
 	return "role"
-}
\ No newline at end of file
+}
@@ -0,0 +1,21 @@
+package models
+
+// 角色权限的授权效果
+const (
+	PermissionEffectAllow = "allow"
+	PermissionEffectDeny  = "deny"
+)
+
+// RolePermission 角色权限关联，Effect 为 deny 时显式拒绝该权限
+//
+// 用户的任一角色（含继承的角色）拒绝某权限时，即使其他角色授予了该权限也不生效。
+type RolePermission struct {
+	RoleID       int    `gorm:"primaryKey" json:"role_id"`
+	PermissionID int    `gorm:"primaryKey" json:"permission_id"`
+	Effect       string `gorm:"type:enum('allow','deny');not null;default:allow" json:"effect"`
+}
+
+// TableName 指定表名
+func (RolePermission) TableName() string {
+	return "role_permission"
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// 租户状态
+const (
+	TenantStatusActive   = "active"
+	TenantStatusDisabled = "disabled"
+)
+
+// DefaultTenantID 默认租户ID，多租户改造前的存量数据均归属该租户，同时作为平台租户管理其他租户
+const DefaultTenantID = 1
+
+// Tenant 租户模型
+type Tenant struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
+	Code      string    `gorm:"size:64;not null;unique" json:"code" example:"acme"`
+	Name      string    `gorm:"size:255;not null" json:"name" example:"ACME 公司"`
+	Status    string    `gorm:"type:enum('active','disabled');not null;default:'active'" json:"status" example:"active"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Tenant) TableName() string {
+	return "tenants"
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"tenant-center/reqctx"
+)
+
+// TenantScope 租户隔离插件
+//
+// 对带有 TenantID 字段的模型，按 context 中的租户ID自动追加 tenant_id 查询条件，
+// 创建记录时自动填充 tenant_id。context 中没有租户ID时（如登录、系统任务）不做处理。
+// 原生SQL（Exec/Raw）不经过模型解析，需要调用方自行保证租户隔离。
+type TenantScope struct{}
+
+// Name 插件名称
+func (TenantScope) Name() string {
+	return "tenant_scope"
+}
+
+// Initialize 注册租户隔离回调
+func (TenantScope) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("tenant:stamp", stampTenant); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", filterTenant); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", filterTenant); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:delete", filterTenant); err != nil {
+		return err
+	}
+	return cb.Row().Before("gorm:row").Register("tenant:row", filterTenant)
+}
+
+// tenantField 获取当前语句模型的租户字段
+func tenantField(db *gorm.DB) *schema.Field {
+	if db.Statement.Schema == nil {
+		return nil
+	}
+	return db.Statement.Schema.LookUpField("TenantID")
+}
+
+// filterTenant 追加 tenant_id 查询条件
+func filterTenant(db *gorm.DB) {
+	field := tenantField(db)
+	if field == nil {
+		return
+	}
+	tenantID, ok := reqctx.TenantID(db.Statement.Context)
+	if !ok {
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenantID},
+	}})
+}
+
+// stampTenant 创建记录时填充 tenant_id
+func stampTenant(db *gorm.DB) {
+	field := tenantField(db)
+	if field == nil {
+		return
+	}
+	tenantID, ok := reqctx.TenantID(db.Statement.Context)
+	if !ok {
+		return
+	}
+
+	ctx := db.Statement.Context
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elem := reflect.Indirect(rv.Index(i))
+			if _, isZero := field.ValueOf(ctx, elem); isZero {
+				if err := field.Set(ctx, elem, tenantID); err != nil {
+					db.AddError(err)
+					return
+				}
+			}
+		}
+	case reflect.Struct:
+		if _, isZero := field.ValueOf(ctx, rv); isZero {
+			if err := field.Set(ctx, rv, tenantID); err != nil {
+				db.AddError(err)
+			}
+		}
+	}
+}
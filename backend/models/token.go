@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken 刷新令牌，只保存令牌的哈希值
+//
+// 每次刷新都会签发新的刷新令牌并标记旧令牌已使用，同一登录会话轮换出的令牌共享 FamilyID。
+// 已使用或已吊销的令牌再次出现时视为泄露，整个 FamilyID 下的令牌都会被吊销。
+type RefreshToken struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index" json:"user_id"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	FamilyID  string     `gorm:"size:32;not null;index" json:"family_id"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_token"
+}
+
+// RevokedToken 已吊销的访问令牌（jti黑名单），过期后可清理
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:32" json:"jti"`
+	UserID    int       `gorm:"not null" json:"user_id"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName 指定表名
+func (RevokedToken) TableName() string {
+	return "revoked_token"
+}
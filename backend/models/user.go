@@ -7,12 +7,16 @@ import (
 
 // User 用户模型
 type User struct {
-	ID        int       `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
-	Username  string    `gorm:"size:255;not null;unique" json:"username" example:"admin"`
-	Password  string    `gorm:"size:255;not null" json:"password,omitempty" example:"password123"`
-	Roles     []Role    `gorm:"many2many:user_role;" json:"roles,omitempty"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	ID        int            `gorm:"primaryKey;autoIncrement" json:"id" example:"1"`
+	TenantID  int            `gorm:"not null;default:1;index" json:"tenant_id" example:"1"`
+	Username  string         `gorm:"size:255;not null;uniqueIndex:idx_user_username_alive" json:"username" example:"admin"` // 未删除的用户中全局唯一，登录时据此确定所属租户
+	Password  string         `gorm:"size:255;not null" json:"password,omitempty" example:"password123"`
+	Roles     []Role         `gorm:"many2many:user_role;" json:"roles,omitempty"`
+	Version   int            `gorm:"not null;default:1" json:"version" example:"1"` // 乐观锁版本，修改信息或角色绑定时加一
+	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP;ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Alive     *bool          `gorm:"->;type:tinyint(1) GENERATED ALWAYS AS (IF(deleted_at IS NULL, 1, NULL)) STORED;uniqueIndex:idx_user_username_alive" json:"-"` // 未删除时为1、删除后为NULL，使回收站中的用户不占用用户名
 }
 
 // TableName 指定表名
@@ -0,0 +1,28 @@
+package models
+
+// 生效权限投影中一行的结果
+const (
+	EffectiveAllow      = "allow"       // 授予且未被任何角色拒绝
+	EffectiveDeny       = "deny"        // 被角色显式拒绝
+	EffectiveSuperAdmin = "super_admin" // 用户拥有超级管理员角色，此时 permission_id 为 0
+)
+
+// UserEffectivePermission 用户生效权限的物化投影，由用户角色、角色继承、角色权限和权限层级计算得出
+//
+// 每行记录一个用户直接绑定的角色对一项权限的最终结果，角色继承、授予模式展开和拒绝覆盖均已计算在内。
+// 投影在绑定关系和权限变更的同一事务中刷新，权限校验和菜单路由只按 user_id 查询这张表。
+type UserEffectivePermission struct {
+	TenantID     int    `gorm:"not null;index" json:"tenant_id"`
+	UserID       int    `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	PermissionID int    `gorm:"primaryKey;autoIncrement:false" json:"permission_id"`
+	RoleID       int    `gorm:"primaryKey;autoIncrement:false" json:"role_id"` // 用户直接绑定的角色，权限可能来自它继承的祖先角色
+	Effect       string `gorm:"type:enum('allow','deny','super_admin');not null" json:"effect"`
+	Code         string `gorm:"size:255;not null" json:"code"`
+	Type         string `gorm:"size:20;not null" json:"type"`
+	MenuID       *int   `json:"menu_id"`
+}
+
+// TableName 指定表名
+func (UserEffectivePermission) TableName() string {
+	return "user_effective_permission"
+}
@@ -0,0 +1,66 @@
+package reqctx
+
+import "context"
+
+// tenantKey 租户ID在context中的键
+type tenantKey struct{}
+
+// WithTenantID 返回携带租户ID的context
+func WithTenantID(ctx context.Context, tenantID int) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantID 获取context中的租户ID，未设置时 ok 为 false
+func TenantID(ctx context.Context) (tenantID int, ok bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	tenantID, ok = ctx.Value(tenantKey{}).(int)
+	return tenantID, ok
+}
+
+// Actor 发起请求的登录用户
+type Actor struct {
+	UserID   int
+	Username string
+}
+
+// actorKey 操作人在context中的键
+type actorKey struct{}
+
+// WithActor 返回携带操作人的context
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom 获取context中的操作人，未登录的请求 ok 为 false
+func ActorFrom(ctx context.Context) (actor Actor, ok bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	actor, ok = ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// RequestInfo 请求元信息
+type RequestInfo struct {
+	RequestID string
+	ClientIP  string
+}
+
+// requestInfoKey 请求元信息在context中的键
+type requestInfoKey struct{}
+
+// WithRequestInfo 返回携带请求元信息的context
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom 获取context中的请求元信息
+func RequestInfoFrom(ctx context.Context) (info RequestInfo, ok bool) {
+	if ctx == nil {
+		return RequestInfo{}, false
+	}
+	info, ok = ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
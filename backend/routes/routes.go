@@ -3,83 +3,154 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"tenant-center/config"
 	"tenant-center/controllers"
+	"tenant-center/keyring"
 	"tenant-center/middleware"
+	"tenant-center/services"
 )
 
 // SetupRoutes 设置路由
-func SetupRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, ring *keyring.Keyring) {
+	tokenService := services.NewTokenService(db, cfg, ring)
+
 	// 创建控制器实例
-	userController := controllers.NewUserController(db)
-	roleController := controllers.NewRoleController(db)
-	permissionController := controllers.NewPermissionController(db)
-	menuController := controllers.NewMenuController(db)
-	buttonController := controllers.NewButtonController(db)
+	userController := controllers.NewUserController(db, cfg, tokenService)
+	roleController := controllers.NewRoleController(db, cfg)
+	permissionController := controllers.NewPermissionController(db, cfg)
+	menuController := controllers.NewMenuController(db, cfg)
+	buttonController := controllers.NewButtonController(db, cfg)
+	tenantController := controllers.NewTenantController(db, cfg)
+	recycleBinController := controllers.NewRecycleBinController(db, cfg)
+	auditController := controllers.NewAuditController(db)
+	authzController := controllers.NewAuthzController(db, cfg)
+	jwksController := controllers.NewJWKSController(ring)
+
+	// 权限校验中间件
+	authz := middleware.NewAuthorizer(services.NewPermissionResolver(db, cfg))
+
+	// 为每个请求分配请求ID，写入响应头和审计日志
+	r.Use(middleware.RequestID())
+
+	// 公布令牌校验公钥，供下游服务验证本服务签发的令牌
+	r.GET("/.well-known/jwks.json", jwksController.GetJWKS)
 
 	// 公开路由组
 	public := r.Group("/api")
 	{
 		// 用户登录
 		public.POST("/login", userController.Login)
+		// 刷新令牌
+		public.POST("/token/refresh", userController.RefreshToken)
 	}
 
 	// 需要认证的路由组
 	protected := r.Group("/api")
-	protected.Use(middleware.JWTAuth())
+	protected.Use(middleware.JWTAuth(tokenService))
 	{
+		// 退出登录
+		protected.POST("/logout", userController.Logout)
+
 		// 用户相关路由
 		user := protected.Group("/users")
 		{
-			user.POST("", userController.CreateUser)
-			user.PUT("/:id", userController.UpdateUser)
-			user.POST("/:id/roles", userController.BindRoles)
+			user.POST("", authz.RequirePermission("user:create"), userController.CreateUser)
+			user.PUT("/:id", authz.RequirePermission("user:update"), userController.UpdateUser)
+			user.DELETE("/:id", authz.RequirePermission("user:delete"), userController.DeleteUser)
+			user.GET("detail/:id/", authz.RequirePermission("user:view"), userController.GetDetail)
+			user.POST("/:id/roles", authz.RequirePermission("user:bind-role"), userController.BindRoles)
+			user.PATCH("/:id/roles", authz.RequirePermission("user:bind-role"), userController.PatchRoles)
+			// 当前用户自己的路由数据，登录即可访问
 			user.GET("/routes", userController.GetRoutes)
-			user.POST("/page", userController.PageUsers)
+			user.GET("/me/permissions", userController.GetMyPermissions)
+			user.POST("/page", authz.RequirePermission("user:list"), userController.PageUsers)
 		}
 
 		// 角色相关路由
 		role := protected.Group("/roles")
 		{
-			role.POST("", roleController.CreateRole)
-			role.PUT("/:id", roleController.UpdateRole)
-			role.GET("detail/:id/", roleController.GetDetail)
-			role.POST("/page", roleController.PageRoles)
-			role.POST("/:id/bindPermissions", roleController.BindPermissions)
-			role.GET("/:id/permissions", roleController.GetRolePermissions)
+			role.POST("", authz.RequirePermission("role:create"), roleController.CreateRole)
+			role.PUT("/:id", authz.RequirePermission("role:update"), roleController.UpdateRole)
+			role.DELETE("/:id", authz.RequirePermission("role:delete"), roleController.DeleteRole)
+			role.GET("detail/:id/", authz.RequirePermission("role:view"), roleController.GetDetail)
+			role.POST("/page", authz.RequirePermission("role:list"), roleController.PageRoles)
+			role.POST("/:id/bindPermissions", authz.RequirePermission("role:bind-permission"), roleController.BindPermissions)
+			role.GET("/:id/permissions", authz.RequirePermission("role:view"), roleController.GetRolePermissions)
+			role.PATCH("/:id/permissions", authz.RequirePermission("role:bind-permission"), roleController.PatchPermissions)
+			role.POST("/:id/parents", authz.RequirePermission("role:bind-parent"), roleController.BindParents)
+			role.GET("/:id/effective-permissions", authz.RequirePermission("role:view"), roleController.GetEffectivePermissions)
 		}
 
 		// 权限相关路由
 		permission := protected.Group("/permissions")
 		{
-			permission.POST("", permissionController.CreatePermission)
-			permission.GET("detail/:id/", permissionController.GetPermissionDetail)
-			permission.PUT("/:id", permissionController.UpdatePermission)
-			permission.POST("page", permissionController.PagePermissions)
-			permission.GET("/type/:type", permissionController.GetPermissionsByType)
+			permission.POST("", authz.RequirePermission("permission:create"), permissionController.CreatePermission)
+			permission.GET("detail/:id/", authz.RequirePermission("permission:view"), permissionController.GetPermissionDetail)
+			permission.PUT("/:id", authz.RequirePermission("permission:update"), permissionController.UpdatePermission)
+			permission.DELETE("/:id", authz.RequirePermission("permission:delete"), permissionController.DeletePermission)
+			permission.POST("page", authz.RequirePermission("permission:list"), permissionController.PagePermissions)
+			permission.GET("/type/:type", authz.RequirePermission("permission:list"), permissionController.GetPermissionsByType)
+			permission.GET("/expand", authz.RequirePermission("permission:list"), permissionController.ExpandPermission)
 		}
 
 		// 菜单相关路由
 		menu := protected.Group("/menus")
 		{
-			menu.POST("", menuController.CreateMenu)
-			menu.PUT("/:id", menuController.UpdateMenu)
-			menu.GET("detail/:id/", menuController.GetDetail)
-			menu.POST("/page", menuController.ListMenus)
-			menu.GET("/parent/:parentId", menuController.GetMenusByParentID)
-			menu.POST("/:id/permission", menuController.BindPermission)
-			menu.GET("/:id/permissions", menuController.GetMenuPermissions)
+			menu.POST("", authz.RequirePermission("menu:create"), menuController.CreateMenu)
+			menu.PUT("/:id", authz.RequirePermission("menu:update"), menuController.UpdateMenu)
+			menu.DELETE("/:id", authz.RequirePermission("menu:delete"), menuController.DeleteMenu)
+			menu.GET("detail/:id/", authz.RequirePermission("menu:view"), menuController.GetDetail)
+			menu.POST("/page", authz.RequirePermission("menu:list"), menuController.ListMenus)
+			menu.GET("/tree", authz.RequirePermission("menu:list"), menuController.GetMenuTree)
+			menu.POST("/:id/move", authz.RequirePermission("menu:update"), menuController.MoveMenu)
+			menu.GET("/parent/:parentId", authz.RequirePermission("menu:list"), menuController.GetMenusByParentID)
+			menu.POST("/:id/permission", authz.RequirePermission("menu:bind-permission"), menuController.BindPermission)
+			menu.GET("/:id/permissions", authz.RequirePermission("menu:view"), menuController.GetMenuPermissions)
 		}
 
 		// 按钮相关路由
 		button := protected.Group("/buttons")
 		{
-			button.POST("", buttonController.CreateButton)
-			button.PUT("/:id", buttonController.UpdateButton)
-			button.GET("detail/:id/", buttonController.GetDetail)
-			button.GET("/menu/:menuId", buttonController.GetButtonsByMenuID)
-			button.POST("/:id/permission", buttonController.BindPermission)
-			button.GET("/:id/permissions", buttonController.GetButtonPermissions)
-			button.POST("/page", buttonController.ListButtons)
+			button.POST("", authz.RequirePermission("button:create"), buttonController.CreateButton)
+			button.PUT("/:id", authz.RequirePermission("button:update"), buttonController.UpdateButton)
+			button.DELETE("/:id", authz.RequirePermission("button:delete"), buttonController.DeleteButton)
+			button.GET("detail/:id/", authz.RequirePermission("button:view"), buttonController.GetDetail)
+			button.GET("/menu/:menuId", authz.RequirePermission("button:list"), buttonController.GetButtonsByMenuID)
+			button.POST("/:id/permission", authz.RequirePermission("button:bind-permission"), buttonController.BindPermission)
+			button.GET("/:id/permissions", authz.RequirePermission("button:view"), buttonController.GetButtonPermissions)
+			button.POST("/page", authz.RequirePermission("button:list"), buttonController.ListButtons)
+		}
+
+		// 回收站相关路由
+		recycleBin := protected.Group("/recycle-bin")
+		{
+			recycleBin.POST("/page", authz.RequirePermission("recycle-bin:list"), recycleBinController.PageDeleted)
+			recycleBin.POST("/:type/:id/restore", authz.RequirePermission("recycle-bin:restore"), recycleBinController.Restore)
+			recycleBin.DELETE("/:type/:id", authz.RequirePermission("recycle-bin:purge"), recycleBinController.Purge)
+		}
+
+		// 审计日志相关路由
+		auditLog := protected.Group("/audit-logs")
+		{
+			auditLog.POST("/page", authz.RequirePermission("audit-log:list"), auditController.PageAuditLogs)
+		}
+
+		// 权限判定路由，供下游服务查询用户是否拥有权限，以及解释用户的授权来源
+		authzCheck := protected.Group("/authz")
+		{
+			authzCheck.POST("/check", authz.RequirePermission("authz:check"), authzController.Check)
+			authzCheck.POST("/check/batch", authz.RequirePermission("authz:check"), authzController.BatchCheck)
+			authzCheck.POST("/explain", authz.RequirePermission("authz:explain"), authzController.Explain)
+		}
+
+		// 租户相关路由，仅平台租户可访问
+		tenant := protected.Group("/tenants")
+		tenant.Use(middleware.PlatformTenantOnly())
+		{
+			tenant.POST("", authz.RequirePermission("tenant:create"), tenantController.CreateTenant)
+			tenant.PUT("/:id", authz.RequirePermission("tenant:update"), tenantController.UpdateTenant)
+			tenant.GET("detail/:id/", authz.RequirePermission("tenant:view"), tenantController.GetDetail)
+			tenant.POST("/page", authz.RequirePermission("tenant:list"), tenantController.PageTenants)
 		}
 	}
 }
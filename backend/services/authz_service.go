@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"tenant-center/config"
+	"tenant-center/models"
+	"tenant-center/reqctx"
+)
+
+// 供下游服务调用的权限判定原因，补充 PermissionResolver 的判定原因
+const (
+	DecisionReasonTenantInactive  = "tenant_inactive"   // 租户不存在或已停用
+	DecisionReasonSubjectNotFound = "subject_not_found" // 用户不存在或不属于该租户
+)
+
+// AuthzCheck 一次权限判定请求
+type AuthzCheck struct {
+	Subject    int    // 用户ID
+	TenantID   int    // 用户所属租户
+	Permission string // 权限编码
+	Resource   string // 资源标识，原样返回
+}
+
+// AuthzResult 权限判定结果
+type AuthzResult struct {
+	Subject  int    `json:"subject" example:"1"`
+	TenantID int    `json:"tenant_id" example:"1"`
+	Resource string `json:"resource,omitempty" example:"order:1024"`
+	Decision
+}
+
+// AuthzService 权限判定服务，供下游服务查询用户是否拥有权限，与接口权限校验共用同一个权限解析器
+type AuthzService struct {
+	db       *gorm.DB
+	resolver *PermissionResolver
+}
+
+// NewAuthzService 创建权限判定服务实例
+func NewAuthzService(db *gorm.DB, cfg *config.Config) *AuthzService {
+	return &AuthzService{
+		db:       db,
+		resolver: NewPermissionResolver(db, cfg),
+	}
+}
+
+// WithContext 返回绑定请求上下文的服务实例
+func (s *AuthzService) WithContext(ctx context.Context) *AuthzService {
+	return &AuthzService{
+		db:       s.db.WithContext(ctx),
+		resolver: s.resolver.WithContext(ctx),
+	}
+}
+
+// Check 判定一组权限请求，结果与请求一一对应
+//
+// 同一租户下同一用户的请求合并判定，用户的授权数据只加载一次。
+func (s *AuthzService) Check(checks []AuthzCheck) ([]AuthzResult, error) {
+	type subjectKey struct{ tenantID, subject int }
+	groups := make(map[subjectKey][]int)
+	var order []subjectKey
+	for i, check := range checks {
+		key := subjectKey{check.TenantID, check.Subject}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	results := make([]AuthzResult, len(checks))
+	for _, key := range order {
+		indexes := groups[key]
+		codes := make([]string, len(indexes))
+		for i, index := range indexes {
+			codes[i] = checks[index].Permission
+		}
+
+		decisions, err := s.decide(key.tenantID, key.subject, codes)
+		if err != nil {
+			return nil, err
+		}
+		for i, index := range indexes {
+			results[index] = AuthzResult{
+				Subject:  key.subject,
+				TenantID: key.tenantID,
+				Resource: checks[index].Resource,
+				Decision: decisions[i],
+			}
+		}
+	}
+	return results, nil
+}
+
+// decide 在指定租户下判定用户的一组权限
+func (s *AuthzService) decide(tenantID, userID int, codes []string) ([]Decision, error) {
+	deny := func(reason string) []Decision {
+		decisions := make([]Decision, len(codes))
+		for i, code := range codes {
+			decisions[i] = Decision{Permission: code, Reason: reason}
+		}
+		return decisions
+	}
+
+	var tenant models.Tenant
+	if err := s.db.First(&tenant, tenantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return deny(DecisionReasonTenantInactive), nil
+		}
+		return nil, err
+	}
+	if tenant.Status != models.TenantStatusActive {
+		return deny(DecisionReasonTenantInactive), nil
+	}
+
+	// 切换到被判定用户所在的租户，用户、角色、权限查询按该租户隔离
+	ctx := reqctx.WithTenantID(s.db.Statement.Context, tenantID)
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return deny(DecisionReasonSubjectNotFound), nil
+	}
+
+	return s.resolver.WithContext(ctx).Decide(userID, codes)
+}
+
+// Explain 列出当前租户下用户获得或被拒绝目标权限的全部路径，用户不存在时返回 gorm.ErrRecordNotFound
+func (s *AuthzService) Explain(userID int, target ExplainTarget) (*Explanation, error) {
+	if err := s.db.Select("id").First(&models.User{}, userID).Error; err != nil {
+		return nil, err
+	}
+	return s.resolver.Explain(userID, target)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"gorm.io/gorm"
+)
+
+// 关联增量修改相关错误
+var (
+	ErrRoleNotFound       = errors.New("角色不存在")
+	ErrPermissionNotFound = errors.New("权限不存在")
+	ErrMenuNotFound       = errors.New("菜单不存在")
+	ErrButtonNotFound     = errors.New("按钮不存在")
+	ErrAddRemoveConflict  = errors.New("同一项不能同时添加和移除")
+)
+
+// checkIDsExist 校验ID均存在于当前租户，model 需带有租户字段，不存在时返回 notFound
+func checkIDsExist(tx *gorm.DB, model interface{}, ids []int, notFound error) error {
+	ids = uniqueInts(ids)
+	if len(ids) == 0 {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(model).Where("id IN ?", ids).Count(&count).Error; err != nil {
+		return err
+	}
+	if count != int64(len(ids)) {
+		return notFound
+	}
+	return nil
+}
+
+// checkIDExists 校验非 0 的ID存在于当前租户，model 需带有租户字段，不存在时返回 notFound
+func checkIDExists(tx *gorm.DB, model interface{}, id int, notFound error) error {
+	if id == 0 {
+		return nil
+	}
+	return checkIDsExist(tx, model, []int{id}, notFound)
+}
+
+// checkAddRemove 校验同一ID没有同时出现在添加和移除中
+func checkAddRemove(add, remove []int) error {
+	removed := make(map[int]bool, len(remove))
+	for _, id := range remove {
+		removed[id] = true
+	}
+	for _, id := range add {
+		if removed[id] {
+			return ErrAddRemoveConflict
+		}
+	}
+	return nil
+}
+
+// removedAny 判断 before 中是否有ID不在 after 中
+func removedAny(before, after []int) bool {
+	kept := make(map[int]bool, len(after))
+	for _, id := range after {
+		kept[id] = true
+	}
+	for _, id := range before {
+		if !kept[id] {
+			return true
+		}
+	}
+	return false
+}
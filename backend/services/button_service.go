@@ -1,26 +1,49 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"gorm.io/gorm"
+	"tenant-center/config"
 	"tenant-center/models"
 )
 
+// ErrButtonInUse 按钮仍绑定了权限，需要级联删除
+var ErrButtonInUse = errors.New("按钮已绑定权限，不能删除")
+
 // ButtonService 按钮服务
 type ButtonService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	resolver *PermissionResolver
 }
 
 // NewButtonService 创建按钮服务实例
-func NewButtonService(db *gorm.DB) *ButtonService {
-	return &ButtonService{db: db}
+func NewButtonService(db *gorm.DB, cfg *config.Config) *ButtonService {
+	return &ButtonService{db: db, resolver: NewPermissionResolver(db, cfg)}
+}
+
+// WithContext 返回绑定请求上下文的服务实例，数据库操作按上下文中的租户自动隔离
+func (s *ButtonService) WithContext(ctx context.Context) *ButtonService {
+	return &ButtonService{db: s.db.WithContext(ctx), resolver: s.resolver.WithContext(ctx)}
 }
 
-// CreateButton 创建按钮
+// CreateButton 创建按钮，所属菜单不存在于当前租户时返回 ErrMenuNotFound
 func (s *ButtonService) CreateButton(button *models.Button) error {
-	return s.db.Create(button).Error
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		// 校验所属菜单属于当前租户
+		if err := checkIDsExist(tx, &models.Menu{}, []int{button.MenuID}, ErrMenuNotFound); err != nil {
+			return err
+		}
+		if err := tx.Create(button).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourceButton, button.ID, models.AuditActionCreate, nil, button)
+	})
 }
 
-// UpdateButton 更新按钮信息
+// UpdateButton 更新按钮信息，button.Version 非 0 时校验版本，更新成功后写回新版本
+//
+// 所属菜单不存在于当前租户时返回 ErrMenuNotFound。
 func (s *ButtonService) UpdateButton(button *models.Button) error {
 	// 创建一个map来存储需要更新的字段
 	updates := map[string]interface{}{
@@ -29,8 +52,30 @@ func (s *ButtonService) UpdateButton(button *models.Button) error {
 		"menu_id":         button.MenuID,
 	}
 
-	// 使用 Updates 方法只更新指定字段，让 GORM 自动处理时间戳
-	return s.db.Model(button).Updates(updates).Error
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		var before models.Button
+		if err := tx.First(&before, button.ID).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(before.Version, button.Version); err != nil {
+			return err
+		}
+		if err := checkIDsExist(tx, &models.Menu{}, []int{button.MenuID}, ErrMenuNotFound); err != nil {
+			return err
+		}
+
+		// 只更新指定字段并递增版本，让 GORM 自动处理时间戳
+		if err := updateVersioned(tx, button, before.Version, updates); err != nil {
+			return err
+		}
+
+		var after models.Button
+		if err := tx.First(&after, button.ID).Error; err != nil {
+			return err
+		}
+		button.Version = after.Version
+		return recordAudit(tx, models.ResourceButton, button.ID, models.AuditActionUpdate, &before, &after)
+	})
 }
 
 // GetButtonByID 根据ID获取按钮
@@ -42,23 +87,60 @@ func (s *ButtonService) GetButtonByID(id int) (*models.Button, error) {
 	return &button, nil
 }
 
-// ListButtons 获取按钮列表
-func (s *ButtonService) ListButtons(page, pageSize int) ([]models.Button, int64, error) {
-	var buttons []models.Button
-	var total int64
+// buttonListSpec 按钮列表的搜索、筛选和排序规则
+var buttonListSpec = &listSpec{
+	keywordColumns: []string{"name", "action", "permission_code"},
+	filters: map[string]func(db *gorm.DB, value interface{}) *gorm.DB{
+		"menu_id": eqFilter("menu_id"),
+	},
+	sortColumns: commonSortColumns(map[string]string{"name": "name", "menu_id": "menu_id"}),
+	defaultSort: []string{"id"},
+}
 
-	// 计算总记录数
-	if err := s.db.Model(&models.Button{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+// ListButtons 获取按钮列表，支持关键字搜索、字段筛选和多字段排序
+func (s *ButtonService) ListButtons(query ListQuery) ([]models.Button, PageInfo, error) {
+	var buttons []models.Button
+	info, err := pageQuery(s.db.Model(&models.Button{}), query, buttonListSpec, &buttons)
+	if err != nil {
+		return nil, PageInfo{}, err
 	}
+	return buttons, info, nil
+}
 
-	// 获取分页数据
-	offset := (page - 1) * pageSize
-	if err := s.db.Offset(offset).Limit(pageSize).Find(&buttons).Error; err != nil {
-		return nil, 0, err
-	}
+// DeleteButton 将按钮移入回收站
+//
+// 按钮绑定了权限时默认拒绝删除；cascade 为 true 时一并删除这些权限及其角色关联。
+func (s *ButtonService) DeleteButton(buttonID int, cascade bool) error {
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		tx = deleteSession(tx)
+		var button models.Button
+		if err := tx.First(&button, buttonID).Error; err != nil {
+			return err
+		}
+
+		var permissionIDs []int
+		if err := tx.Model(&models.Permission{}).Where("button_id = ?", buttonID).Pluck("id", &permissionIDs).Error; err != nil {
+			return err
+		}
+		if len(permissionIDs) > 0 && !cascade {
+			return ErrButtonInUse
+		}
+
+		if err := deletePermissions(tx, permissionIDs); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Button{}, buttonID).Error; err != nil {
+			return err
+		}
+		if len(permissionIDs) > 0 {
+			if err := s.resolver.withDB(tx).refreshTenant(); err != nil {
+				return err
+			}
+		}
 
-	return buttons, total, nil
+		before := &cascadeSnapshot{Record: &button, PermissionIDs: permissionIDs}
+		return recordAudit(tx, models.ResourceButton, buttonID, models.AuditActionDelete, before, nil)
+	})
 }
 
 // GetButtonsByMenuID 获取指定菜单的按钮列表
@@ -72,6 +154,15 @@ func (s *ButtonService) GetButtonsByMenuID(menuID int) ([]models.Button, error)
 
 // BindButtonPermission 为按钮绑定权限
 func (s *ButtonService) BindButtonPermission(buttonID int, permissionCode string, permissionName string) error {
+	// 校验按钮属于当前租户
+	if _, err := s.GetButtonByID(buttonID); err != nil {
+		return err
+	}
+
+	if err := validatePermissionCode(permissionCode); err != nil {
+		return err
+	}
+
 	permission := &models.Permission{
 		Code:     permissionCode,
 		Name:     permissionName,
@@ -79,7 +170,16 @@ func (s *ButtonService) BindButtonPermission(buttonID int, permissionCode string
 		ButtonID: &buttonID,
 	}
 
-	return s.db.Create(permission).Error
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Create(permission).Error; err != nil {
+			return err
+		}
+		// 新权限可能落入已授予的通配权限或父权限的范围
+		if err := s.resolver.withDB(tx).refreshCreatedPermission(permission); err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourcePermission, permission.ID, models.AuditActionCreate, nil, permission)
+	})
 }
 
 // GetButtonPermissions 获取按钮的权限列表
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 列表查询相关错误
+var (
+	ErrInvalidSortField = errors.New("不支持的排序字段")
+	ErrInvalidFilter    = errors.New("不支持的筛选条件")
+	ErrInvalidCursor    = errors.New("无效的分页游标")
+)
+
+// ListQuery 列表查询条件，所有 Page* 列表接口共用
+type ListQuery struct {
+	Page        int
+	PageSize    int
+	Keyword     string                 // 关键字，在资源的名称、编码等字段中模糊匹配
+	Filters     map[string]interface{} // 字段筛选，键需在资源的白名单中，值为 nil 时忽略
+	CreatedFrom *time.Time             // 创建时间起（含）
+	CreatedTo   *time.Time             // 创建时间止（不含）
+	Sort        []string               // 排序字段，"-" 前缀表示倒序，如 ["-created_at", "name"]
+	Cursor      *string                // 非 nil 时使用游标分页，空字符串表示第一页；游标分页不统计总数，忽略 Page
+}
+
+// PageInfo 列表查询的分页信息
+type PageInfo struct {
+	Total      *int64 // 总记录数，游标分页时为 nil
+	NextCursor string // 游标分页的下一页游标，没有更多数据时为空
+}
+
+// listSpec 资源的列表查询规则
+type listSpec struct {
+	keywordColumns []string                                                 // 参与关键字搜索的列
+	filters        map[string]func(db *gorm.DB, value interface{}) *gorm.DB // 允许的筛选条件
+	sortColumns    map[string]string                                        // 允许排序的字段 → 列名
+	defaultSort    []string                                                 // 未指定排序时的默认排序
+	cursorColumn   string                                                   // 游标分页的时间列，为空时使用 created_at
+}
+
+// eqFilter 返回按列等值筛选的条件
+func eqFilter(column string) func(db *gorm.DB, value interface{}) *gorm.DB {
+	return func(db *gorm.DB, value interface{}) *gorm.DB {
+		return db.Where(column+" = ?", value)
+	}
+}
+
+// commonSortColumns 所有资源都支持的排序字段
+func commonSortColumns(extra map[string]string) map[string]string {
+	columns := map[string]string{
+		"id":         "id",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+	for field, column := range extra {
+		columns[field] = column
+	}
+	return columns
+}
+
+// apply 按查询条件追加关键字、筛选和创建时间条件
+func (q ListQuery) apply(db *gorm.DB, spec *listSpec) (*gorm.DB, error) {
+	if keyword := strings.TrimSpace(q.Keyword); keyword != "" && len(spec.keywordColumns) > 0 {
+		pattern := "%" + escapeLike(keyword) + "%"
+		group := db.Session(&gorm.Session{NewDB: true})
+		for i, column := range spec.keywordColumns {
+			if i == 0 {
+				group = group.Where(column+" LIKE ?", pattern)
+			} else {
+				group = group.Or(column+" LIKE ?", pattern)
+			}
+		}
+		db = db.Where(group)
+	}
+
+	for name, value := range q.Filters {
+		if value == nil {
+			continue
+		}
+		filter, ok := spec.filters[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFilter, name)
+		}
+		db = filter(db, value)
+	}
+
+	if q.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *q.CreatedFrom)
+	}
+	if q.CreatedTo != nil {
+		db = db.Where("created_at < ?", *q.CreatedTo)
+	}
+	return db, nil
+}
+
+// order 按排序字段追加排序，始终以 id 兜底保证分页稳定
+func (q ListQuery) order(db *gorm.DB, spec *listSpec) (*gorm.DB, error) {
+	fields := q.Sort
+	if len(fields) == 0 {
+		fields = spec.defaultSort
+	}
+
+	hasID := false
+	for _, field := range fields {
+		direction := "ASC"
+		if strings.HasPrefix(field, "-") {
+			direction = "DESC"
+			field = field[1:]
+		}
+		column, ok := spec.sortColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSortField, field)
+		}
+		if column == "id" {
+			hasID = true
+		}
+		db = db.Order(column + " " + direction)
+	}
+	if !hasID {
+		db = db.Order("id ASC")
+	}
+	return db, nil
+}
+
+// pageQuery 执行分页查询，将当前页数据写入 dest
+//
+// 页码分页返回总数；传入游标时改用游标分页，不执行 count 查询，返回下一页游标。
+func pageQuery(db *gorm.DB, q ListQuery, spec *listSpec, dest interface{}) (PageInfo, error) {
+	query, err := q.apply(db, spec)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	if q.Cursor != nil {
+		return cursorQuery(query, q, spec, dest)
+	}
+
+	query, err = q.order(query, spec)
+	if err != nil {
+		return PageInfo{}, err
+	}
+
+	// 计算总记录数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return PageInfo{}, err
+	}
+
+	// 获取分页数据
+	offset := (q.Page - 1) * q.PageSize
+	if err := query.Offset(offset).Limit(q.PageSize).Find(dest).Error; err != nil {
+		return PageInfo{}, err
+	}
+	return PageInfo{Total: &total}, nil
+}
+
+// listCursor 游标内容，记录上一页最后一条记录的时间列和ID，以及生成游标时的排序
+type listCursor struct {
+	Sort string    `json:"s"` // 排序的时间列，"-" 前缀表示倒序
+	Time time.Time `json:"t"`
+	ID   int       `json:"i"`
+}
+
+// encodeCursor 将游标编码为不透明的字符串
+func encodeCursor(c listCursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor 解析 encodeCursor 生成的游标，游标不是按 sort 排序生成的时同样视为无效
+func decodeCursor(s, sort string) (listCursor, error) {
+	var c listCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == 0 || c.Sort != sort {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// cursorQuery 按 (时间列, id) 进行游标分页，多取一条判断是否还有下一页
+func cursorQuery(db *gorm.DB, q ListQuery, spec *listSpec, dest interface{}) (PageInfo, error) {
+	column := spec.cursorColumn
+	if column == "" {
+		column = "created_at"
+	}
+
+	// 只能按游标时间列排序，未指定排序时沿用默认排序的方向
+	desc := len(spec.defaultSort) > 0 && strings.HasPrefix(spec.defaultSort[0], "-")
+	switch {
+	case len(q.Sort) == 0:
+	case len(q.Sort) == 1 && strings.TrimPrefix(q.Sort[0], "-") == column:
+		desc = strings.HasPrefix(q.Sort[0], "-")
+	default:
+		return PageInfo{}, fmt.Errorf("%w: 游标分页只支持按 %s 排序", ErrInvalidSortField, column)
+	}
+
+	direction, op, sort := "ASC", ">", column
+	if desc {
+		direction, op, sort = "DESC", "<", "-"+column
+	}
+
+	if *q.Cursor != "" {
+		cursor, err := decodeCursor(*q.Cursor, sort)
+		if err != nil {
+			return PageInfo{}, err
+		}
+		db = db.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, op),
+			cursor.Time, cursor.Time, cursor.ID)
+	}
+
+	if err := db.Order(column + " " + direction).Order("id " + direction).
+		Limit(q.PageSize + 1).Find(dest).Error; err != nil {
+		return PageInfo{}, err
+	}
+
+	rows := reflect.ValueOf(dest).Elem()
+	if rows.Len() <= q.PageSize {
+		return PageInfo{}, nil
+	}
+	rows.Set(rows.Slice(0, q.PageSize))
+
+	// 以当前页最后一条记录生成下一页游标
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(dest); err != nil {
+		return PageInfo{}, err
+	}
+	timeField, idField := stmt.Schema.LookUpField(column), stmt.Schema.LookUpField("id")
+	if timeField == nil || idField == nil {
+		return PageInfo{}, fmt.Errorf("列表结果缺少游标字段 %s 或 id", column)
+	}
+	last := reflect.Indirect(rows.Index(q.PageSize - 1))
+	timeValue, _ := timeField.ValueOf(db.Statement.Context, last)
+	idValue, _ := idField.ValueOf(db.Statement.Context, last)
+
+	cursor := listCursor{Sort: sort}
+	switch v := timeValue.(type) {
+	case time.Time:
+		cursor.Time = v
+	case gorm.DeletedAt:
+		cursor.Time = v.Time
+	}
+	cursor.ID, _ = idValue.(int)
+
+	next, err := encodeCursor(cursor)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	return PageInfo{NextCursor: next}, nil
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
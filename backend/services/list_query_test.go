@@ -0,0 +1,71 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := listCursor{Sort: "-created_at", Time: time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC), ID: 42}
+	encoded, err := encodeCursor(want)
+	if err != nil {
+		t.Fatalf("encodeCursor: %v", err)
+	}
+
+	got, err := decodeCursor(encoded, "-created_at")
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if got.Sort != want.Sort || !got.Time.Equal(want.Time) || got.ID != want.ID {
+		t.Errorf("decodeCursor = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursorRejectsInvalid(t *testing.T) {
+	valid, err := encodeCursor(listCursor{Sort: "created_at", Time: time.Now(), ID: 1})
+	if err != nil {
+		t.Fatalf("encodeCursor: %v", err)
+	}
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   string
+	}{
+		{"not base64", "!!!", "created_at"},
+		{"not json", raw("created_at,1"), "created_at"},
+		{"missing id", raw(`{"s":"created_at","t":"2024-01-01T00:00:00Z"}`), "created_at"},
+		{"wrong time type", raw(`{"s":"created_at","t":1,"i":1}`), "created_at"},
+		{"truncated", valid[:len(valid)-3], "created_at"},
+		{"opposite direction", valid, "-created_at"},
+		{"other column", valid, "deleted_at"},
+		{"no sort recorded", raw(`{"t":"2024-01-01T00:00:00Z","i":1}`), "created_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.cursor, tt.sort); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor(%q, %q) error = %v, want ErrInvalidCursor", tt.cursor, tt.sort, err)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"admin", "admin"},
+		{"50%", `50\%`},
+		{"user_name", `user\_name`},
+		{`a\b`, `a\\b`},
+		{`%_\`, `\%\_\\`},
+	}
+	for _, tt := range tests {
+		if got := escapeLike(tt.in); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,30 +1,62 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"gorm.io/gorm"
+	"tenant-center/config"
 	"tenant-center/models"
 )
 
+// 菜单层级相关错误
+var (
+	ErrMenuParentNotFound = errors.New("上级菜单不存在")
+	ErrMenuCycle          = errors.New("不能将菜单移动到自身或其子菜单下")
+)
+
+// MenuInUseError 菜单仍有子菜单、按钮或权限引用，需要级联删除
+type MenuInUseError struct {
+	Children    int64 `json:"children"`
+	Buttons     int64 `json:"buttons"`
+	Permissions int64 `json:"permissions"`
+}
+
+func (e *MenuInUseError) Error() string {
+	return fmt.Sprintf("菜单存在%d个子菜单、%d个按钮、%d个权限，不能删除", e.Children, e.Buttons, e.Permissions)
+}
+
 // MenuService 菜单服务
 type MenuService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	resolver *PermissionResolver
 }
 
 // NewMenuService 创建菜单服务实例
-func NewMenuService(db *gorm.DB) *MenuService {
-	return &MenuService{db: db}
+func NewMenuService(db *gorm.DB, cfg *config.Config) *MenuService {
+	return &MenuService{db: db, resolver: NewPermissionResolver(db, cfg)}
+}
+
+// WithContext 返回绑定请求上下文的服务实例，数据库操作按上下文中的租户自动隔离
+func (s *MenuService) WithContext(ctx context.Context) *MenuService {
+	return &MenuService{db: s.db.WithContext(ctx), resolver: s.resolver.WithContext(ctx)}
 }
 
 // CreateMenu 创建菜单
 func (s *MenuService) CreateMenu(menu *models.Menu) error {
-	return s.db.Create(menu).Error
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Create(menu).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourceMenu, menu.ID, models.AuditActionCreate, nil, menu)
+	})
 }
 
-// UpdateMenu 更新菜单信息
+// UpdateMenu 更新菜单信息，menu.Version 非 0 时校验版本，更新成功后写回新版本
 func (s *MenuService) UpdateMenu(menu *models.Menu) error {
 	// 创建一个map来存储需要更新的字段
 	updates := map[string]interface{}{
-		"parent_id":           menu.ParentID,
+		"parent_id":          menu.ParentID,
 		"name":               menu.Name,
 		"path":               menu.Path,
 		"component":          menu.Component,
@@ -35,8 +67,30 @@ func (s *MenuService) UpdateMenu(menu *models.Menu) error {
 		"button_association": menu.ButtonAssociation,
 	}
 
-	// 使用 Updates 方法只更新指定字段，让 GORM 自动处理时间戳
-	return s.db.Model(menu).Updates(updates).Error
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		var before models.Menu
+		if err := tx.First(&before, menu.ID).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(before.Version, menu.Version); err != nil {
+			return err
+		}
+		if err := checkMenuParent(tx, menu.ID, menuParentID(menu.ParentID)); err != nil {
+			return err
+		}
+
+		// 只更新指定字段并递增版本，让 GORM 自动处理时间戳
+		if err := updateVersioned(tx, menu, before.Version, updates); err != nil {
+			return err
+		}
+
+		var after models.Menu
+		if err := tx.First(&after, menu.ID).Error; err != nil {
+			return err
+		}
+		menu.Version = after.Version
+		return recordAudit(tx, models.ResourceMenu, menu.ID, models.AuditActionUpdate, &before, &after)
+	})
 }
 
 // GetMenuByID 根据ID获取菜单
@@ -48,36 +102,296 @@ func (s *MenuService) GetMenuByID(id int) (*models.Menu, error) {
 	return &menu, nil
 }
 
-// ListMenus 获取菜单列表
-func (s *MenuService) ListMenus(page, pageSize int) ([]models.Menu, int64, error) {
-	var menus []models.Menu
-	var total int64
+// menuListSpec 菜单列表的搜索、筛选和排序规则
+var menuListSpec = &listSpec{
+	keywordColumns: []string{"name", "path"},
+	filters: map[string]func(db *gorm.DB, value interface{}) *gorm.DB{
+		"parent_id":  eqFilter("parent_id"),
+		"is_visible": eqFilter("is_visible"),
+	},
+	sortColumns: commonSortColumns(map[string]string{"name": "name", "order": "`order`", "parent_id": "parent_id"}),
+	defaultSort: []string{"parent_id", "order"},
+}
 
-	// 计算总记录数
-	if err := s.db.Model(&models.Menu{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+// ListMenus 获取菜单列表，支持关键字搜索、字段筛选和多字段排序
+func (s *MenuService) ListMenus(query ListQuery) ([]models.Menu, PageInfo, error) {
+	var menus []models.Menu
+	info, err := pageQuery(s.db.Model(&models.Menu{}), query, menuListSpec, &menus)
+	if err != nil {
+		return nil, PageInfo{}, err
 	}
+	return menus, info, nil
+}
 
-	// 获取分页数据
-	offset := (page - 1) * pageSize
-	if err := s.db.Offset(offset).Limit(pageSize).Find(&menus).Error; err != nil {
-		return nil, 0, err
-	}
+// DeleteMenu 将菜单移入回收站
+//
+// 菜单存在子菜单、按钮或权限时默认拒绝删除并返回 *MenuInUseError；cascade 为 true 时
+// 删除整棵子菜单树，以及这些菜单下的按钮、菜单和按钮的权限及其角色关联。
+func (s *MenuService) DeleteMenu(menuID int, cascade bool) error {
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		tx = deleteSession(tx)
+		var menu models.Menu
+		if err := tx.First(&menu, menuID).Error; err != nil {
+			return err
+		}
+
+		if !cascade {
+			inUse := &MenuInUseError{}
+			if err := tx.Model(&models.Menu{}).Where("parent_id = ?", menuID).Count(&inUse.Children).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Button{}).Where("menu_id = ?", menuID).Count(&inUse.Buttons).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Permission{}).Where("menu_id = ?", menuID).Count(&inUse.Permissions).Error; err != nil {
+				return err
+			}
+			if inUse.Children > 0 || inUse.Buttons > 0 || inUse.Permissions > 0 {
+				return inUse
+			}
+			if err := tx.Delete(&models.Menu{}, menuID).Error; err != nil {
+				return err
+			}
+			return recordAudit(tx, models.ResourceMenu, menuID, models.AuditActionDelete, &menu, nil)
+		}
 
-	return menus, total, nil
+		menuIDs, err := collectMenuSubtree(tx, menuID)
+		if err != nil {
+			return err
+		}
+
+		var buttonIDs []int
+		if err := tx.Model(&models.Button{}).Where("menu_id IN ?", menuIDs).Pluck("id", &buttonIDs).Error; err != nil {
+			return err
+		}
+
+		// 分组条件，保证租户条件作用于整个 OR 表达式
+		owned := tx.Where("menu_id IN ?", menuIDs)
+		if len(buttonIDs) > 0 {
+			owned = owned.Or("button_id IN ?", buttonIDs)
+		}
+		var permissionIDs []int
+		if err := tx.Model(&models.Permission{}).Where(owned).Pluck("id", &permissionIDs).Error; err != nil {
+			return err
+		}
+
+		if err := deletePermissions(tx, permissionIDs); err != nil {
+			return err
+		}
+		if len(buttonIDs) > 0 {
+			if err := tx.Delete(&models.Button{}, buttonIDs).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&models.Menu{}, menuIDs).Error; err != nil {
+			return err
+		}
+		if len(permissionIDs) > 0 {
+			if err := s.resolver.withDB(tx).refreshTenant(); err != nil {
+				return err
+			}
+		}
+
+		before := &cascadeSnapshot{Record: &menu, MenuIDs: menuIDs, ButtonIDs: buttonIDs, PermissionIDs: permissionIDs}
+		return recordAudit(tx, models.ResourceMenu, menuID, models.AuditActionDelete, before, nil)
+	})
+}
+
+// collectMenuSubtree 返回菜单及其所有子孙菜单的ID
+func collectMenuSubtree(tx *gorm.DB, menuID int) ([]int, error) {
+	ids := []int{menuID}
+	visited := map[int]bool{menuID: true}
+	frontier := []int{menuID}
+
+	for len(frontier) > 0 {
+		var children []int
+		if err := tx.Model(&models.Menu{}).Where("parent_id IN ?", frontier).Pluck("id", &children).Error; err != nil {
+			return nil, err
+		}
+
+		frontier = frontier[:0]
+		for _, id := range children {
+			// 防御脏数据中的环
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			ids = append(ids, id)
+			frontier = append(frontier, id)
+		}
+	}
+	return ids, nil
 }
 
 // GetMenusByParentID 获取指定父级菜单的子菜单列表
 func (s *MenuService) GetMenusByParentID(parentID int) ([]models.Menu, error) {
 	var menus []models.Menu
-	if err := s.db.Where("parent_id = ?", parentID).Order("order").Find(&menus).Error; err != nil {
+	if err := siblingMenus(s.db, parentID).Order("`order`, id").Find(&menus).Error; err != nil {
 		return nil, err
 	}
 	return menus, nil
 }
 
+// MenuTreeNode 菜单树节点
+type MenuTreeNode struct {
+	models.Menu
+	Children []*MenuTreeNode `json:"children,omitempty"`
+}
+
+// GetMenuTree 获取完整的菜单树，同级菜单按 order 排序
+//
+// 上级菜单已不存在的菜单挂在根节点下，避免脏数据中的菜单从树中消失。
+func (s *MenuService) GetMenuTree() ([]*MenuTreeNode, error) {
+	var menus []models.Menu
+	if err := s.db.Order("`order`, id").Find(&menus).Error; err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[int]*MenuTreeNode, len(menus))
+	for i := range menus {
+		nodes[menus[i].ID] = &MenuTreeNode{Menu: menus[i]}
+	}
+
+	roots := make([]*MenuTreeNode, 0)
+	for i := range menus {
+		node := nodes[menus[i].ID]
+		parent, ok := nodes[menuParentID(menus[i].ParentID)]
+		if !ok {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return roots, nil
+}
+
+// MoveMenu 将菜单移动到指定上级菜单下的指定位置，并重新编排新旧两组同级菜单的 order
+//
+// parentID 为 0 表示移动到根级；position 为在新的同级菜单中的下标（从 0 开始），
+// 为负数或超出范围时放在最后。
+func (s *MenuService) MoveMenu(menuID, parentID, position int) error {
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		var before models.Menu
+		if err := tx.First(&before, menuID).Error; err != nil {
+			return err
+		}
+		if err := checkMenuParent(tx, menuID, parentID); err != nil {
+			return err
+		}
+
+		var siblings []int
+		if err := siblingMenus(tx, parentID).Where("id <> ?", menuID).
+			Order("`order`, id").Pluck("id", &siblings).Error; err != nil {
+			return err
+		}
+		if position < 0 || position > len(siblings) {
+			position = len(siblings)
+		}
+		siblings = append(siblings[:position], append([]int{menuID}, siblings[position:]...)...)
+
+		if err := updateVersioned(tx, &models.Menu{ID: menuID}, before.Version, map[string]interface{}{"parent_id": parentID}); err != nil {
+			return err
+		}
+		if err := renumberMenus(tx, siblings); err != nil {
+			return err
+		}
+
+		// 收拢原同级菜单的序号
+		if oldParentID := menuParentID(before.ParentID); oldParentID != parentID {
+			var oldSiblings []int
+			if err := siblingMenus(tx, oldParentID).Order("`order`, id").Pluck("id", &oldSiblings).Error; err != nil {
+				return err
+			}
+			if err := renumberMenus(tx, oldSiblings); err != nil {
+				return err
+			}
+		}
+
+		var after models.Menu
+		if err := tx.First(&after, menuID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourceMenu, menuID, models.AuditActionMove, &before, &after)
+	})
+}
+
+// menuParentID 返回菜单的上级菜单ID，根级菜单的 parent_id 可能为 NULL 或 0，统一返回 0
+func menuParentID(parentID *int) int {
+	if parentID == nil {
+		return 0
+	}
+	return *parentID
+}
+
+// siblingMenus 返回指定上级菜单下的子菜单查询
+func siblingMenus(db *gorm.DB, parentID int) *gorm.DB {
+	if parentID == 0 {
+		return db.Model(&models.Menu{}).Where("(parent_id = 0 OR parent_id IS NULL)")
+	}
+	return db.Model(&models.Menu{}).Where("parent_id = ?", parentID)
+}
+
+// checkMenuParent 校验上级菜单存在，且不是菜单自身或其子孙菜单
+func checkMenuParent(tx *gorm.DB, menuID, parentID int) error {
+	visited := make(map[int]bool)
+	for id := parentID; id != 0; {
+		if id == menuID {
+			return ErrMenuCycle
+		}
+		// 已有数据中存在环时同样拒绝，避免死循环
+		if visited[id] {
+			return ErrMenuCycle
+		}
+		visited[id] = true
+
+		var parent models.Menu
+		if err := tx.Select("id, parent_id").First(&parent, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMenuParentNotFound
+			}
+			return err
+		}
+		id = menuParentID(parent.ParentID)
+	}
+	return nil
+}
+
+// renumberMenus 按给定顺序将同级菜单的 order 重新编号为 1..n，只更新发生变化的菜单
+func renumberMenus(tx *gorm.DB, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var menus []models.Menu
+	if err := tx.Select("id, `order`").Where("id IN ?", ids).Find(&menus).Error; err != nil {
+		return err
+	}
+	current := make(map[int]int, len(menus))
+	for _, menu := range menus {
+		current[menu.ID] = menu.Order
+	}
+
+	for i, id := range ids {
+		if current[id] == i+1 {
+			continue
+		}
+		if err := tx.Model(&models.Menu{}).Where("id = ?", id).Update("order", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // BindMenuPermission 为菜单绑定权限
 func (s *MenuService) BindMenuPermission(menuID int, permissionCode string, permissionName string) error {
+	// 校验菜单属于当前租户
+	if _, err := s.GetMenuByID(menuID); err != nil {
+		return err
+	}
+
+	if err := validatePermissionCode(permissionCode); err != nil {
+		return err
+	}
+
 	permission := &models.Permission{
 		Code:   permissionCode,
 		Name:   permissionName,
@@ -85,7 +399,16 @@ func (s *MenuService) BindMenuPermission(menuID int, permissionCode string, perm
 		MenuID: &menuID,
 	}
 
-	return s.db.Create(permission).Error
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Create(permission).Error; err != nil {
+			return err
+		}
+		// 新权限可能落入已授予的通配权限或父权限的范围
+		if err := s.resolver.withDB(tx).refreshCreatedPermission(permission); err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourcePermission, permission.ID, models.AuditActionCreate, nil, permission)
+	})
 }
 
 // GetMenuPermissions 获取菜单的权限列表
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"log"
+	"sync"
+	"tenant-center/config"
+	"tenant-center/models"
+	"tenant-center/reqctx"
+	"time"
+)
+
+// policyCacheName 权限缓存注册为 GORM 插件时使用的名称
+const policyCacheName = "tenant-center:policy_cache"
+
+// policyCacheMaxUsers 缓存的用户数上限，超出时整体清空，避免大量用户访问后占用过多内存
+const policyCacheMaxUsers = 10000
+
+// 缓存的结果类型
+const (
+	cachedSuperAdmin = "super_admin"
+	cachedCodes      = "codes"
+	cachedMenuGrants = "menu_grants"
+	cachedRoutes     = "routes"
+	cachedDecision   = "decision:" // 后接权限编码
+)
+
+// policyCacheKey 缓存按租户和用户区分
+type policyCacheKey struct {
+	tenantID int
+	userID   int
+}
+
+// PolicyCache 进程内的权限缓存，按租户和用户缓存权限判定、权限编码、菜单授权和路由树
+//
+// 授权数据在修改它的事务中递增数据库里的全局策略版本（policy_version），各实例通过 Watch 轮询该版本，
+// 发现变化即清空缓存；本实例的修改在事务提交后立即清空。缓存以代数区分新旧，计算开始前记下代数，
+// 写入时代数已变化的结果直接丢弃，避免把修改提交前读到的数据留在缓存中。
+//
+// 通过 db.Use 注册后，NewPermissionResolver 创建的解析器都会使用该缓存；缓存中的切片和映射不可修改。
+type PolicyCache struct {
+	db       *gorm.DB
+	interval time.Duration
+
+	mu      sync.RWMutex
+	version int64  // 最近一次轮询到的策略版本
+	epoch   uint64 // 缓存代数，每次清空时加一
+	entries map[policyCacheKey]map[string]interface{}
+}
+
+// NewPolicyCache 创建权限缓存实例
+func NewPolicyCache(cfg *config.Config) *PolicyCache {
+	return &PolicyCache{
+		interval: cfg.Authz.CachePollInterval.Duration,
+		entries:  make(map[policyCacheKey]map[string]interface{}),
+	}
+}
+
+// Name 实现 gorm.Plugin 接口
+func (c *PolicyCache) Name() string {
+	return policyCacheName
+}
+
+// Initialize 实现 gorm.Plugin 接口，记下用于轮询策略版本的数据库连接
+func (c *PolicyCache) Initialize(db *gorm.DB) error {
+	c.db = db
+	return nil
+}
+
+// Watch 按配置的间隔轮询策略版本，直到 ctx 结束
+func (c *PolicyCache) Watch(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		if err := c.poll(ctx); err != nil {
+			// 无法确认其他实例是否修改过授权数据，清空缓存回退到直接查询
+			log.Println("轮询策略版本失败:", err)
+			c.invalidate()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll 读取数据库中的策略版本，与上次不同时清空缓存
+func (c *PolicyCache) poll(ctx context.Context) error {
+	var policy models.PolicyVersion
+	if err := c.db.WithContext(ctx).Take(&policy, models.PolicyVersionID).Error; err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if policy.Version != c.version {
+		c.version = policy.Version
+		c.reset()
+	}
+	return nil
+}
+
+// invalidate 清空缓存
+func (c *PolicyCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reset()
+}
+
+// reset 清空缓存并进入下一代，调用方需持有写锁
+func (c *PolicyCache) reset() {
+	c.epoch++
+	c.entries = make(map[policyCacheKey]map[string]interface{})
+}
+
+// currentEpoch 返回当前的缓存代数
+func (c *PolicyCache) currentEpoch() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.epoch
+}
+
+// get 返回缓存的结果
+func (c *PolicyCache) get(key policyCacheKey, name string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok := c.entries[key][name]
+	return value, ok
+}
+
+// put 写入计算结果，epoch 为计算开始前的代数，期间缓存被清空过时丢弃结果
+func (c *PolicyCache) put(key policyCacheKey, name string, epoch uint64, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if epoch != c.epoch {
+		return
+	}
+	values, ok := c.entries[key]
+	if !ok {
+		if len(c.entries) >= policyCacheMaxUsers {
+			c.entries = make(map[policyCacheKey]map[string]interface{})
+		}
+		values = make(map[string]interface{})
+		c.entries[key] = values
+	}
+	values[name] = value
+}
+
+// cached 返回用户在当前租户下名为 name 的缓存结果，未命中时调用 compute 计算并写入缓存
+//
+// 未启用缓存或解析器绑定了事务时直接计算，事务中读到的未提交数据不会进入缓存。
+func (r *PermissionResolver) cached(userID int, name string, compute func() (interface{}, error)) (interface{}, error) {
+	if r.cache == nil {
+		return compute()
+	}
+
+	key := r.cacheKey(userID)
+	if value, ok := r.cache.get(key, name); ok {
+		return value, nil
+	}
+	epoch := r.cache.currentEpoch()
+	value, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	r.cache.put(key, name, epoch, value)
+	return value, nil
+}
+
+// cacheKey 返回用户在当前上下文租户下的缓存键，没有租户上下文时租户记为 0
+func (r *PermissionResolver) cacheKey(userID int) policyCacheKey {
+	tenantID, _ := reqctx.TenantID(r.db.Statement.Context)
+	return policyCacheKey{tenantID: tenantID, userID: userID}
+}
+
+// transaction 在事务中修改授权数据，并在同一事务中递增策略版本
+//
+// 提交后立即清空本实例的缓存，其他实例在下一次轮询时发现版本变化。
+func (r *PermissionResolver) transaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		// 放在最后递增，缩短策略版本这一行被锁住的时间
+		return bumpPolicyVersion(tx)
+	})
+	if err == nil && r.cache != nil {
+		r.cache.invalidate()
+	}
+	return err
+}
+
+// bumpPolicyVersion 递增全局策略版本，策略版本表不带租户信息
+func bumpPolicyVersion(tx *gorm.DB) error {
+	return tx.Model(&models.PolicyVersion{}).
+		Where("id = ?", models.PolicyVersionID).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidPermissionCode 权限编码格式不合法
+var ErrInvalidPermissionCode = errors.New("权限编码格式不合法，应为以冒号分隔的小写字母、数字、下划线或连字符段，如 user:create，段内可用 * 通配")
+
+// permissionCodeSegment 权限编码中的一段，* 为通配符
+var permissionCodeSegment = regexp.MustCompile(`^[a-z0-9_*-]+$`)
+
+// validatePermissionCode 校验权限编码由至少两段组成，如 module:action
+func validatePermissionCode(code string) error {
+	segments := strings.Split(code, ":")
+	if len(segments) < 2 {
+		return ErrInvalidPermissionCode
+	}
+	for _, segment := range segments {
+		if !permissionCodeSegment.MatchString(segment) {
+			return ErrInvalidPermissionCode
+		}
+	}
+	return nil
+}
+
+// isWildcardCode 判断权限编码是否包含通配符
+func isWildcardCode(code string) bool {
+	return strings.Contains(code, "*")
+}
+
+// hasWildcardCode 判断权限编码列表中是否包含通配编码
+func hasWildcardCode(codes []string) bool {
+	for _, code := range codes {
+		if isWildcardCode(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPermissionCode 判断授予的权限编码是否覆盖要求的权限编码
+//
+// 通配符只在段内匹配，不跨越冒号：user:* 匹配 user:create，不匹配 user:profile:edit；
+// *:read 匹配 user:read、role:read。
+func matchPermissionCode(granted, required string) bool {
+	if !isWildcardCode(granted) {
+		return granted == required
+	}
+
+	patterns := strings.Split(granted, ":")
+	segments := strings.Split(required, ":")
+	if len(patterns) != len(segments) {
+		return false
+	}
+	for i, pattern := range patterns {
+		// 存量编码未经校验，* 以外的匹配符按字面匹配
+		if ok, err := path.Match(globLiteral.Replace(pattern), segments[i]); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// globLiteral 转义 path.Match 中 * 以外的匹配符
+var globLiteral = strings.NewReplacer(`\`, `\\`, "?", `\?`, "[", `\[`)
+
+// permissionCodeLike 将通配编码转换为 LIKE 条件，用于在数据库中粗筛候选权限
+func permissionCodeLike(pattern string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", "%")
+	return replacer.Replace(pattern)
+}
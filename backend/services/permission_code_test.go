@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePermissionCode(t *testing.T) {
+	tests := []struct {
+		code  string
+		valid bool
+	}{
+		{"user:create", true},
+		{"user:profile:edit", true},
+		{"user:*", true},
+		{"*:read", true},
+		{"*:*", true},
+		{"user:re*", true},
+		{"order_item:bulk-export", true},
+		{"user", false},
+		{"", false},
+		{":create", false},
+		{"user:", false},
+		{"user::create", false},
+		{"User:create", false},
+		{"user:create ", false},
+		{"user:[ab]", false},
+		{"user:?", false},
+		{"user.create", false},
+	}
+	for _, tt := range tests {
+		err := validatePermissionCode(tt.code)
+		if tt.valid && err != nil {
+			t.Errorf("validatePermissionCode(%q) = %v, want nil", tt.code, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidPermissionCode) {
+			t.Errorf("validatePermissionCode(%q) = %v, want ErrInvalidPermissionCode", tt.code, err)
+		}
+	}
+}
+
+func TestMatchPermissionCode(t *testing.T) {
+	tests := []struct {
+		granted, required string
+		want              bool
+	}{
+		{"user:create", "user:create", true},
+		{"user:create", "user:delete", false},
+		{"user:*", "user:create", true},
+		{"user:*", "role:create", false},
+		// 通配符不跨越冒号，段数不同不匹配
+		{"user:*", "user:profile:edit", false},
+		{"user:*:edit", "user:profile:edit", true},
+		{"user:*:edit", "user:create", false},
+		{"*:read", "user:read", true},
+		{"*:read", "role:read", true},
+		{"*:read", "user:write", false},
+		{"*:read", "user:profile:read", false},
+		{"*:*", "user:create", true},
+		{"user:re*", "user:read", true},
+		{"user:re*", "user:write", false},
+		// 通配编码本身也能被更宽的通配匹配
+		{"*:*", "user:*", true},
+		// 存量编码未经校验，非通配编码按字面比较
+		{"user:[ab]", "user:[ab]", true},
+		{"user:[ab]", "user:a", false},
+		// 通配编码中 * 以外的匹配符按字面匹配
+		{"user:[a*", "user:[abc", true},
+		{"user:[ab]*", "user:a", false},
+		{"user:[ab]*", "user:[ab]x", true},
+		{"user:?*", "user:a", false},
+		{"user:?*", "user:?a", true},
+		{`user:a\*`, `user:a\b`, true},
+		{`user:a\*`, "user:ab", false},
+		{"user:*", "user:[ab]", true},
+	}
+	for _, tt := range tests {
+		if got := matchPermissionCode(tt.granted, tt.required); got != tt.want {
+			t.Errorf("matchPermissionCode(%q, %q) = %v, want %v", tt.granted, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestPermissionCodeLike(t *testing.T) {
+	tests := []struct {
+		pattern, want string
+	}{
+		{"user:create", "user:create"},
+		{"user:*", "user:%"},
+		{"*:read", "%:read"},
+		{"*:*", "%:%"},
+		{"order_item:*", `order\_item:%`},
+		{"user:100%", `user:100\%`},
+		{`user:a\b`, `user:a\\b`},
+	}
+	for _, tt := range tests {
+		if got := permissionCodeLike(tt.pattern); got != tt.want {
+			t.Errorf("permissionCodeLike(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
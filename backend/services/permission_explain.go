@@ -0,0 +1,290 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"tenant-center/config"
+	"tenant-center/models"
+)
+
+// ErrInvalidExplainTarget 解释目标需要且只能指定权限编码、菜单ID、按钮ID中的一个
+var ErrInvalidExplainTarget = errors.New("需要且只能指定权限编码、菜单ID、按钮ID中的一个")
+
+// 授权路径的结果
+const (
+	ExplainOutcomeApplied    = "applied"    // 路径参与了最终结果
+	ExplainOutcomeOverridden = "overridden" // 授予路径被拒绝覆盖
+)
+
+// ExplainTarget 解释的目标，三者只能指定一个
+type ExplainTarget struct {
+	Permission string // 权限编码，可以是接口要求的编码
+	MenuID     int    // 菜单ID，菜单或其任一子菜单被授权时菜单可见
+	ButtonID   int    // 按钮ID
+}
+
+// ExplainRole 授权路径上的角色
+type ExplainRole struct {
+	ID   int    `json:"id" example:"2"`
+	Code string `json:"code" example:"ROLE_MANAGER"`
+	Name string `json:"name" example:"经理"`
+}
+
+// ExplainPermission 授权路径上的权限
+type ExplainPermission struct {
+	ID       int    `json:"id" example:"5"`
+	Code     string `json:"code" example:"user:*"`
+	Name     string `json:"name" example:"用户管理"`
+	Type     string `json:"type" example:"menu"`
+	MenuID   *int   `json:"menu_id,omitempty" example:"1"`
+	ButtonID *int   `json:"button_id,omitempty"`
+	Relation string `json:"relation" enums:"bound,wildcard,child,parent" example:"bound"` // 与上一项权限的关系
+}
+
+// ExplainPath 一条授予或拒绝目标的路径：用户角色 →（继承的角色）→ 绑定的权限 →（展开得到的权限）
+type ExplainPath struct {
+	Effect      string              `json:"effect" enums:"allow,deny" example:"allow"`
+	Outcome     string              `json:"outcome" enums:"applied,overridden" example:"applied"`
+	Roles       []ExplainRole       `json:"roles"`       // 从用户直接绑定的角色到持有绑定的角色
+	Permissions []ExplainPermission `json:"permissions"` // 从绑定的权限到匹配目标的权限
+}
+
+// Explanation 用户对目标的授权判定及其全部路径
+type Explanation struct {
+	UserID  int           `json:"user_id" example:"1"`
+	Allowed bool          `json:"allowed" example:"true"`
+	Reason  string        `json:"reason" example:"granted"`      // 同 Decision.Reason
+	RoleID  int           `json:"role_id,omitempty" example:"2"` // 决定结果的用户角色
+	Paths   []ExplainPath `json:"paths"`
+}
+
+// Explain 列出用户获得或被拒绝目标权限的全部路径，并标明最终结果和起决定作用的角色
+func (r *PermissionResolver) Explain(userID int, target ExplainTarget) (*Explanation, error) {
+	specified := 0
+	for _, set := range []bool{target.Permission != "", target.MenuID != 0, target.ButtonID != 0} {
+		if set {
+			specified++
+		}
+	}
+	if specified != 1 {
+		return nil, ErrInvalidExplainTarget
+	}
+
+	closure, err := r.userRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	direct := make([]int, 0, len(closure))
+	var roleIDs []int
+	for roleID, roles := range closure {
+		direct = append(direct, roleID)
+		roleIDs = append(roleIDs, roles...)
+	}
+	sort.Ints(direct)
+	roleIDs = uniqueInts(roleIDs)
+
+	parents, err := loadRoleParents(r.db, direct)
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := loadRoleGrants(r.db, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+	hierarchy, err := loadPermissionHierarchy(r.db)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := r.explainMatcher(target)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &Explanation{UserID: userID, Paths: make([]ExplainPath, 0)}
+	if err := r.explainDecision(explanation, userID, target, matches); err != nil {
+		return nil, err
+	}
+
+	roles := make(map[int]models.Role, len(roleIDs))
+	if len(roleIDs) > 0 {
+		var list []models.Role
+		if err := r.db.Where("id IN ?", roleIDs).Find(&list).Error; err != nil {
+			return nil, err
+		}
+		for _, role := range list {
+			roles[role.ID] = role
+		}
+	}
+
+	// 先收集拒绝路径，授予路径匹配到被拒绝的权限时记为被覆盖
+	denied := make(map[int]bool)
+	var allowPaths, denyPaths []ExplainPath
+	for _, effect := range []string{models.PermissionEffectDeny, models.PermissionEffectAllow} {
+		mode, held := r.grantMode, bindings.allow
+		if effect == models.PermissionEffectDeny {
+			held = bindings.deny
+			if mode == config.GrantModeAncestors {
+				mode = config.GrantModeExact
+			}
+		}
+
+		for _, directID := range direct {
+			for _, holderID := range closure[directID] {
+				bound := append([]int(nil), held[holderID]...)
+				sort.Ints(bound)
+				for _, permissionID := range bound {
+					steps := hierarchy.trace(permissionID, mode)
+					reached := make([]int, 0)
+					for id := range steps {
+						if matches(hierarchy.permissions[id]) {
+							reached = append(reached, id)
+						}
+					}
+					sort.Ints(reached)
+
+					for _, id := range reached {
+						path := ExplainPath{
+							Effect:      effect,
+							Outcome:     ExplainOutcomeApplied,
+							Roles:       explainRoles(roles, rolePath(parents, directID, holderID)),
+							Permissions: explainPermissions(hierarchy, steps, id),
+						}
+						if effect == models.PermissionEffectDeny {
+							denied[id] = true
+							denyPaths = append(denyPaths, path)
+							continue
+						}
+						// 按编码判定时拒绝可能来自匹配同一编码的其他权限，以判定结果为准
+						if denied[id] || (target.Permission != "" && explanation.Reason == DecisionReasonDenied) {
+							path.Outcome = ExplainOutcomeOverridden
+						}
+						allowPaths = append(allowPaths, path)
+					}
+				}
+			}
+		}
+	}
+	explanation.Paths = append(append(explanation.Paths, denyPaths...), allowPaths...)
+	return explanation, nil
+}
+
+// explainMatcher 返回判断权限是否属于解释目标的条件
+func (r *PermissionResolver) explainMatcher(target ExplainTarget) (func(models.Permission) bool, error) {
+	switch {
+	case target.Permission != "":
+		return func(permission models.Permission) bool {
+			return matchPermissionCode(permission.Code, target.Permission)
+		}, nil
+
+	case target.ButtonID != 0:
+		return func(permission models.Permission) bool {
+			return permission.ButtonID != nil && *permission.ButtonID == target.ButtonID
+		}, nil
+
+	default:
+		// 授权菜单的上级菜单自动可见，目标菜单的任一子孙菜单被授权时同样可见
+		var menus []models.Menu
+		if err := r.db.Select("id, parent_id").Find(&menus).Error; err != nil {
+			return nil, err
+		}
+		children := make(map[int][]int)
+		for _, menu := range menus {
+			parentID := menuParentID(menu.ParentID)
+			children[parentID] = append(children[parentID], menu.ID)
+		}
+		subtree := map[int]bool{target.MenuID: true}
+		queue := []int{target.MenuID}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, child := range children[id] {
+				if !subtree[child] {
+					subtree[child] = true
+					queue = append(queue, child)
+				}
+			}
+		}
+		return func(permission models.Permission) bool {
+			return permission.Type == "menu" && permission.MenuID != nil && subtree[*permission.MenuID]
+		}, nil
+	}
+}
+
+// explainDecision 计算用户对目标的最终判定
+//
+// 按编码判定与接口权限校验一致；菜单和按钮只要有一项匹配的权限授予且未被拒绝即可访问。
+func (r *PermissionResolver) explainDecision(explanation *Explanation, userID int, target ExplainTarget, matches func(models.Permission) bool) error {
+	if target.Permission != "" {
+		decisions, err := r.decideLive(userID, []string{target.Permission})
+		if err != nil {
+			return err
+		}
+		explanation.Allowed = decisions[0].Allowed
+		explanation.Reason = decisions[0].Reason
+		explanation.RoleID = decisions[0].RoleID
+		return nil
+	}
+
+	explanation.Reason = DecisionReasonNotGranted
+	superRoleID, err := r.superRoleID(userID)
+	if err != nil {
+		return err
+	}
+	if superRoleID != 0 {
+		explanation.Allowed = true
+		explanation.Reason = DecisionReasonSuperAdmin
+		explanation.RoleID = superRoleID
+		return nil
+	}
+
+	grants, err := r.resolve(userID, nil)
+	if err != nil || grants == nil {
+		return err
+	}
+	allowed := make(map[int][]int)
+	for id, roleIDs := range grants.granting() {
+		if len(grants.denied[id]) == 0 {
+			allowed[id] = roleIDs
+		}
+	}
+	if roleID := grants.matchRole(allowed, matches); roleID != 0 {
+		explanation.Allowed = true
+		explanation.Reason = DecisionReasonGranted
+		explanation.RoleID = roleID
+	} else if roleID := grants.matchRole(grants.denied, matches); roleID != 0 {
+		explanation.Reason = DecisionReasonDenied
+		explanation.RoleID = roleID
+	}
+	return nil
+}
+
+// explainRoles 将角色ID路径转换为路径上的角色
+func explainRoles(roles map[int]models.Role, path []int) []ExplainRole {
+	result := make([]ExplainRole, 0, len(path))
+	for _, id := range path {
+		role := roles[id]
+		result = append(result, ExplainRole{ID: id, Code: role.Code, Name: role.Name})
+	}
+	return result
+}
+
+// explainPermissions 从展开记录中还原由绑定的权限到目标权限的路径
+func explainPermissions(hierarchy *permissionHierarchy, steps map[int]traceStep, target int) []ExplainPermission {
+	var result []ExplainPermission
+	for id := target; id != 0; id = steps[id].from {
+		permission := hierarchy.permissions[id]
+		result = append(result, ExplainPermission{
+			ID:       id,
+			Code:     permission.Code,
+			Name:     permission.Name,
+			Type:     permission.Type,
+			MenuID:   permission.MenuID,
+			ButtonID: permission.ButtonID,
+			Relation: steps[id].relation,
+		})
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
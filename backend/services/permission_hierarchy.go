@@ -0,0 +1,302 @@
+package services
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"sort"
+	"tenant-center/config"
+	"tenant-center/models"
+)
+
+// 权限层级相关错误
+var (
+	ErrPermissionParentNotFound = errors.New("父级权限不存在")
+	ErrPermissionCycle          = errors.New("不能将权限的父级设置为自身或其子孙权限")
+)
+
+// 角色对权限的授权状态
+const (
+	PermissionStateAllowed = "allowed" // 授予且未被拒绝
+	PermissionStateDenied  = "denied"  // 被角色自身或祖先角色显式拒绝
+	PermissionStateUnset   = "unset"   // 未授予也未拒绝
+)
+
+// PermissionTreeNode 角色权限树节点
+type PermissionTreeNode struct {
+	ID            int                  `json:"id"`
+	Name          string               `json:"name"`
+	State         string               `json:"state" enums:"allowed,denied,unset"` // 授权状态，拒绝优先于授予
+	Enable        bool                 `json:"enable"`                             // 角色是否拥有该权限，即 state 为 allowed
+	Own           bool                 `json:"own"`                                // 当前状态来自角色自身的绑定
+	InheritedFrom []int                `json:"inherited_from,omitempty"`           // 当前状态来自这些祖先角色的绑定
+	HalfChecked   bool                 `json:"half_checked"`                       // 子孙权限中只有一部分被授予
+	Icon          string               `json:"icon,omitempty"`
+	Children      []PermissionTreeNode `json:"children"`
+}
+
+// permissionGrants 角色授予和拒绝的权限及其来源，均已按授予模式展开
+type permissionGrants struct {
+	own           map[int]bool  // 角色自身授予的权限
+	inherited     map[int][]int // 权限ID → 授予该权限的祖先角色ID
+	ownDeny       map[int]bool  // 角色自身拒绝的权限
+	inheritedDeny map[int][]int // 权限ID → 拒绝该权限的祖先角色ID
+}
+
+// state 返回权限的授权状态，以及决定该状态的绑定来源
+func (g *permissionGrants) state(id int) (state string, own bool, from []int) {
+	if g.ownDeny[id] || len(g.inheritedDeny[id]) > 0 {
+		return PermissionStateDenied, g.ownDeny[id], g.inheritedDeny[id]
+	}
+	if g.own[id] || len(g.inherited[id]) > 0 {
+		return PermissionStateAllowed, g.own[id], g.inherited[id]
+	}
+	return PermissionStateUnset, false, nil
+}
+
+// permissionHierarchy 当前租户的权限层级
+type permissionHierarchy struct {
+	permissions map[int]models.Permission
+	children    map[int][]int
+	roots       []int // 顶级权限，以及父级已不存在的权限
+}
+
+// loadPermissionHierarchy 加载当前租户的全部权限，按ID排序构建层级
+func loadPermissionHierarchy(db *gorm.DB) (*permissionHierarchy, error) {
+	var permissions []models.Permission
+	if err := db.Order("id").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return newPermissionHierarchy(permissions), nil
+}
+
+// newPermissionHierarchy 根据权限列表构建层级
+func newPermissionHierarchy(permissions []models.Permission) *permissionHierarchy {
+	h := &permissionHierarchy{
+		permissions: make(map[int]models.Permission, len(permissions)),
+		children:    make(map[int][]int),
+	}
+	for _, permission := range permissions {
+		h.permissions[permission.ID] = permission
+	}
+	for _, permission := range permissions {
+		parentID := permissionParentID(permission.ParentID)
+		if _, ok := h.permissions[parentID]; ok {
+			h.children[parentID] = append(h.children[parentID], permission.ID)
+		} else {
+			h.roots = append(h.roots, permission.ID)
+		}
+	}
+	return h
+}
+
+// expand 按授予模式展开直接授予的权限，返回生效的权限ID集合
+//
+// 通配权限先展开为其匹配的全部权限，再与直接授予的权限一起按授予模式展开。
+func (h *permissionHierarchy) expand(granted []int, mode string) map[int]bool {
+	effective := make(map[int]bool, len(granted))
+	var patterns []string
+	for _, id := range granted {
+		if permission, ok := h.permissions[id]; ok {
+			effective[id] = true
+			if isWildcardCode(permission.Code) {
+				patterns = append(patterns, permission.Code)
+			}
+		}
+	}
+	if len(patterns) > 0 {
+		for id, permission := range h.permissions {
+			for _, pattern := range patterns {
+				if matchPermissionCode(pattern, permission.Code) {
+					effective[id] = true
+					break
+				}
+			}
+		}
+	}
+	matched := make([]int, 0, len(effective))
+	for id := range effective {
+		matched = append(matched, id)
+	}
+
+	switch mode {
+	case config.GrantModeSubtree:
+		stack := matched
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, child := range h.children[id] {
+				if !effective[child] {
+					effective[child] = true
+					stack = append(stack, child)
+				}
+			}
+		}
+
+	case config.GrantModeAncestors:
+		for _, id := range matched {
+			// 遇到已生效的上级即停止，其上级链由它自身或之前的遍历负责
+			for parentID := permissionParentID(h.permissions[id].ParentID); ; {
+				if _, ok := h.permissions[parentID]; !ok || effective[parentID] {
+					break
+				}
+				effective[parentID] = true
+				parentID = permissionParentID(h.permissions[parentID].ParentID)
+			}
+		}
+	}
+	return effective
+}
+
+// expandDeny 展开显式拒绝的权限，返回被拒绝的权限ID集合
+//
+// 通配拒绝覆盖其匹配的全部权限，subtree 模式下拒绝父权限即拒绝其子孙权限；拒绝子权限不会牵连上级权限。
+func (h *permissionHierarchy) expandDeny(denied []int, mode string) map[int]bool {
+	if mode == config.GrantModeAncestors {
+		mode = config.GrantModeExact
+	}
+	return h.expand(denied, mode)
+}
+
+// 授权路径中权限之间的关系
+const (
+	ExplainRelationBound    = "bound"    // 角色直接绑定的权限
+	ExplainRelationWildcard = "wildcard" // 被上一项通配权限匹配
+	ExplainRelationChild    = "child"    // subtree 模式下随上一项父权限生效
+	ExplainRelationParent   = "parent"   // ancestors 模式下随上一项子权限生效
+)
+
+// traceStep 展开过程中到达某权限的一步
+type traceStep struct {
+	from     int // 上一项权限ID，绑定的权限为 0
+	relation string
+}
+
+// trace 按 expand 的规则展开单项绑定的权限，记录到达每个权限的方式，用于还原授权路径
+func (h *permissionHierarchy) trace(start int, mode string) map[int]traceStep {
+	permission, ok := h.permissions[start]
+	if !ok {
+		return nil
+	}
+
+	steps := map[int]traceStep{start: {relation: ExplainRelationBound}}
+	matched := []int{start}
+	if isWildcardCode(permission.Code) {
+		for id, candidate := range h.permissions {
+			if id != start && matchPermissionCode(permission.Code, candidate.Code) {
+				steps[id] = traceStep{from: start, relation: ExplainRelationWildcard}
+				matched = append(matched, id)
+			}
+		}
+	}
+
+	switch mode {
+	case config.GrantModeSubtree:
+		queue := append([]int(nil), matched...)
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, child := range h.children[id] {
+				if _, ok := steps[child]; !ok {
+					steps[child] = traceStep{from: id, relation: ExplainRelationChild}
+					queue = append(queue, child)
+				}
+			}
+		}
+
+	case config.GrantModeAncestors:
+		for _, id := range matched {
+			for parentID := permissionParentID(h.permissions[id].ParentID); ; {
+				if _, ok := h.permissions[parentID]; !ok {
+					break
+				}
+				if _, ok := steps[parentID]; ok {
+					break
+				}
+				steps[parentID] = traceStep{from: id, relation: ExplainRelationParent}
+				id, parentID = parentID, permissionParentID(h.permissions[parentID].ParentID)
+			}
+		}
+	}
+	return steps
+}
+
+// codes 返回权限ID集合对应的权限编码，按编码排序
+func (h *permissionHierarchy) codes(ids map[int]bool) []string {
+	codes := make([]string, 0, len(ids))
+	for id := range ids {
+		codes = append(codes, h.permissions[id].Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// tree 构建权限树，并标记每个权限的授权状态及其来源
+func (h *permissionHierarchy) tree(grants *permissionGrants) []PermissionTreeNode {
+	var build func(id int) (node PermissionTreeNode, total, granted int)
+	build = func(id int) (PermissionTreeNode, int, int) {
+		permission := h.permissions[id]
+		state, own, from := grants.state(id)
+		node := PermissionTreeNode{
+			ID:            permission.ID,
+			Name:          permission.Name,
+			State:         state,
+			Enable:        state == PermissionStateAllowed,
+			Own:           own,
+			InheritedFrom: from,
+			Children:      make([]PermissionTreeNode, 0),
+		}
+
+		// 统计子孙权限的总数和已授予数
+		total, granted := 0, 0
+		for _, childID := range h.children[id] {
+			child, childTotal, childGranted := build(childID)
+			node.Children = append(node.Children, child)
+			total += childTotal + 1
+			granted += childGranted
+			if child.Enable {
+				granted++
+			}
+		}
+		node.HalfChecked = granted > 0 && granted < total
+		return node, total, granted
+	}
+
+	nodes := make([]PermissionTreeNode, 0, len(h.roots))
+	for _, id := range h.roots {
+		node, _, _ := build(id)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// permissionParentID 返回权限的父级ID，顶级权限的 parent_id 可能为 NULL 或 0，统一返回 0
+func permissionParentID(parentID *int) int {
+	if parentID == nil {
+		return 0
+	}
+	return *parentID
+}
+
+// checkPermissionParent 校验父级权限存在，且不是权限自身或其子孙权限
+//
+// 新建权限时 permissionID 传 0，只校验父级是否存在。
+func checkPermissionParent(tx *gorm.DB, permissionID, parentID int) error {
+	visited := make(map[int]bool)
+	for id := parentID; id != 0; {
+		// 已有数据中存在环时同样拒绝，避免死循环
+		if id == permissionID || visited[id] {
+			return ErrPermissionCycle
+		}
+		visited[id] = true
+
+		var parent models.Permission
+		if err := tx.Select("id, parent_id").First(&parent, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPermissionParentNotFound
+			}
+			return err
+		}
+		id = permissionParentID(parent.ParentID)
+	}
+	return nil
+}
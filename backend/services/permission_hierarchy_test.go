@@ -0,0 +1,132 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+	"tenant-center/config"
+	"tenant-center/models"
+	"testing"
+)
+
+// testHierarchy 测试用的权限层级：
+//
+//	1 system:manage
+//	├── 2 user:manage
+//	│   ├── 3 user:create
+//	│   └── 4 user:delete
+//	└── 5 role:manage
+//	    └── 6 role:read
+//	7 audit:read
+//	8 orphan:read（父级 99 不存在）
+//	9 user:*
+//	10 *:read
+func testHierarchy() *permissionHierarchy {
+	parent := func(id int) *int { return &id }
+	return newPermissionHierarchy([]models.Permission{
+		{ID: 1, Code: "system:manage"},
+		{ID: 2, Code: "user:manage", ParentID: parent(1)},
+		{ID: 3, Code: "user:create", ParentID: parent(2)},
+		{ID: 4, Code: "user:delete", ParentID: parent(2)},
+		{ID: 5, Code: "role:manage", ParentID: parent(1)},
+		{ID: 6, Code: "role:read", ParentID: parent(5)},
+		{ID: 7, Code: "audit:read", ParentID: parent(0)},
+		{ID: 8, Code: "orphan:read", ParentID: parent(99)},
+		{ID: 9, Code: "user:*"},
+		{ID: 10, Code: "*:read"},
+	})
+}
+
+// sortedIDs 将权限ID集合转换为有序切片，便于比较和输出
+func sortedIDs(set map[int]bool) []int {
+	ids := make([]int, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func TestNewPermissionHierarchyRoots(t *testing.T) {
+	h := testHierarchy()
+	if want := []int{1, 7, 8, 9, 10}; !reflect.DeepEqual(h.roots, want) {
+		t.Errorf("roots = %v, want %v", h.roots, want)
+	}
+	if want := []int{3, 4}; !reflect.DeepEqual(h.children[2], want) {
+		t.Errorf("children[2] = %v, want %v", h.children[2], want)
+	}
+}
+
+func TestPermissionHierarchyExpand(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		granted []int
+		want    []int
+	}{
+		{"exact", config.GrantModeExact, []int{3}, []int{3}},
+		{"exact parent", config.GrantModeExact, []int{2}, []int{2}},
+		{"unknown permission", config.GrantModeSubtree, []int{99}, []int{}},
+		{"subtree", config.GrantModeSubtree, []int{2}, []int{2, 3, 4}},
+		{"subtree root", config.GrantModeSubtree, []int{1}, []int{1, 2, 3, 4, 5, 6}},
+		{"subtree leaf", config.GrantModeSubtree, []int{6}, []int{6}},
+		{"ancestors", config.GrantModeAncestors, []int{3}, []int{1, 2, 3}},
+		{"ancestors shared chain", config.GrantModeAncestors, []int{3, 6}, []int{1, 2, 3, 5, 6}},
+		{"ancestors missing parent", config.GrantModeAncestors, []int{8}, []int{8}},
+		{"ancestors root", config.GrantModeAncestors, []int{1}, []int{1}},
+		{"wildcard", config.GrantModeExact, []int{9}, []int{2, 3, 4, 9}},
+		{"wildcard first segment", config.GrantModeExact, []int{10}, []int{6, 7, 8, 10}},
+		{"wildcard subtree", config.GrantModeSubtree, []int{10}, []int{6, 7, 8, 10}},
+		{"wildcard ancestors", config.GrantModeAncestors, []int{10}, []int{1, 5, 6, 7, 8, 10}},
+		{"wildcard with exact grant", config.GrantModeExact, []int{9, 7}, []int{2, 3, 4, 7, 9}},
+	}
+	h := testHierarchy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortedIDs(h.expand(tt.granted, tt.mode)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expand(%v, %s) = %v, want %v", tt.granted, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionHierarchyExpandCycle(t *testing.T) {
+	// 已有数据中存在环时展开仍能结束
+	parent := func(id int) *int { return &id }
+	h := newPermissionHierarchy([]models.Permission{
+		{ID: 1, Code: "a:a", ParentID: parent(2)},
+		{ID: 2, Code: "a:b", ParentID: parent(1)},
+	})
+	for _, mode := range []string{config.GrantModeSubtree, config.GrantModeAncestors} {
+		if got, want := sortedIDs(h.expand([]int{1}, mode)), []int{1, 2}; !reflect.DeepEqual(got, want) {
+			t.Errorf("expand([1], %s) = %v, want %v", mode, got, want)
+		}
+	}
+}
+
+func TestPermissionHierarchyExpandDeny(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		denied []int
+		want   []int
+	}{
+		{"exact", config.GrantModeExact, []int{2}, []int{2}},
+		{"subtree", config.GrantModeSubtree, []int{2}, []int{2, 3, 4}},
+		{"subtree root", config.GrantModeSubtree, []int{1}, []int{1, 2, 3, 4, 5, 6}},
+		// 拒绝子权限不会牵连上级权限
+		{"ancestors", config.GrantModeAncestors, []int{3}, []int{3}},
+		{"ancestors parent", config.GrantModeAncestors, []int{2}, []int{2}},
+		{"wildcard", config.GrantModeExact, []int{9}, []int{2, 3, 4, 9}},
+		{"wildcard subtree", config.GrantModeSubtree, []int{10}, []int{6, 7, 8, 10}},
+		{"wildcard ancestors", config.GrantModeAncestors, []int{10}, []int{6, 7, 8, 10}},
+		{"unknown permission", config.GrantModeSubtree, []int{99}, []int{}},
+	}
+	h := testHierarchy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortedIDs(h.expandDeny(tt.denied, tt.mode)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandDeny(%v, %s) = %v, want %v", tt.denied, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,545 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+	"tenant-center/config"
+	"tenant-center/models"
+	"tenant-center/reqctx"
+)
+
+// projectionBatchSize 批量写入投影行时每条 INSERT 的行数
+const projectionBatchSize = 500
+
+// IsSuperAdmin 判断用户是否拥有超级管理员角色，继承超级管理员角色同样视为超级管理员
+func (r *PermissionResolver) IsSuperAdmin(userID int) (bool, error) {
+	value, err := r.cached(userID, cachedSuperAdmin, func() (interface{}, error) {
+		var count int64
+		if err := r.db.Model(&models.UserEffectivePermission{}).
+			Where("user_id = ? AND effect = ?", userID, models.EffectiveSuperAdmin).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+		return count > 0, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return value.(bool), nil
+}
+
+// GetUserPermissionCodes 获取用户通过角色（含继承的角色）获得的全部权限编码，不含被拒绝的权限，按编码排序
+//
+// 授予的通配权限会展开为其当前匹配的具体权限编码，通配编码本身也保留在结果中。
+func (r *PermissionResolver) GetUserPermissionCodes(userID int) ([]string, error) {
+	value, err := r.cached(userID, cachedCodes, func() (interface{}, error) {
+		var codes []string
+		if err := r.db.Model(&models.UserEffectivePermission{}).
+			Where("user_id = ? AND effect = ?", userID, models.EffectiveAllow).
+			Distinct("code").Order("code").
+			Pluck("code", &codes).Error; err != nil {
+			return nil, err
+		}
+		return codes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]string), nil
+}
+
+// Decide 逐个判定用户是否拥有各权限编码，缓存未命中的编码一起查询一次用户的生效权限投影
+//
+// 授予和拒绝的通配权限均按段匹配，拒绝优先；多个角色匹配时取ID最小的用户角色作为判定依据。
+func (r *PermissionResolver) Decide(userID int, codes []string) ([]Decision, error) {
+	if r.cache == nil {
+		return r.decide(userID, codes)
+	}
+
+	key := r.cacheKey(userID)
+	decisions := make([]Decision, len(codes))
+	var missing []string
+	var missingAt []int
+	for i, code := range codes {
+		if value, ok := r.cache.get(key, cachedDecision+code); ok {
+			decisions[i] = value.(Decision)
+			continue
+		}
+		missing = append(missing, code)
+		missingAt = append(missingAt, i)
+	}
+	if len(missing) == 0 {
+		return decisions, nil
+	}
+
+	epoch := r.cache.currentEpoch()
+	computed, err := r.decide(userID, missing)
+	if err != nil {
+		return nil, err
+	}
+	for i, decision := range computed {
+		decisions[missingAt[i]] = decision
+		r.cache.put(key, cachedDecision+decision.Permission, epoch, decision)
+	}
+	return decisions, nil
+}
+
+// decide 查询用户的生效权限投影判定各权限编码
+func (r *PermissionResolver) decide(userID int, codes []string) ([]Decision, error) {
+	decisions := make([]Decision, len(codes))
+	for i, code := range codes {
+		decisions[i] = Decision{Permission: code, Reason: DecisionReasonNotGranted}
+	}
+
+	// 只取可能匹配的行：超级管理员标记、编码完全相同的权限和通配权限
+	var rows []models.UserEffectivePermission
+	if err := r.db.Where("user_id = ?", userID).
+		Where(r.db.Where("effect = ?", models.EffectiveSuperAdmin).Or("code IN ?", codes).Or("code LIKE ?", "%*%")).
+		Order("role_id").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if row.Effect == models.EffectiveSuperAdmin {
+			for i := range decisions {
+				decisions[i].Allowed = true
+				decisions[i].Reason = DecisionReasonSuperAdmin
+				decisions[i].RoleID = row.RoleID
+			}
+			return decisions, nil
+		}
+	}
+
+	for i := range decisions {
+		// 行已按角色ID排序，第一条匹配的拒绝或授予即为ID最小的角色
+		for _, effect := range []string{models.EffectiveDeny, models.EffectiveAllow} {
+			for _, row := range rows {
+				if row.Effect == effect && matchPermissionCode(row.Code, decisions[i].Permission) {
+					decisions[i].Allowed = effect == models.EffectiveAllow
+					decisions[i].Reason = DecisionReasonGranted
+					if effect == models.EffectiveDeny {
+						decisions[i].Reason = DecisionReasonDenied
+					}
+					decisions[i].RoleID = row.RoleID
+					break
+				}
+			}
+			if decisions[i].RoleID != 0 {
+				break
+			}
+		}
+	}
+	return decisions, nil
+}
+
+// GetUserMenuGrants 获取用户通过菜单类型权限可访问的菜单，返回 菜单ID → 授权角色ID列表
+//
+// 授权角色为用户直接绑定的角色，通过继承获得的菜单权限记在继承它的角色名下；
+// 用户的任一角色拒绝了菜单权限时，该权限不再为任何角色提供菜单。
+func (r *PermissionResolver) GetUserMenuGrants(userID int) (map[int][]int, error) {
+	value, err := r.cached(userID, cachedMenuGrants, func() (interface{}, error) {
+		var rows []models.UserEffectivePermission
+		if err := r.db.Select("menu_id, role_id").
+			Where("user_id = ? AND effect = ? AND type = ? AND menu_id IS NOT NULL", userID, models.EffectiveAllow, "menu").
+			Distinct().Order("menu_id, role_id").
+			Find(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		result := make(map[int][]int)
+		for _, row := range rows {
+			result[*row.MenuID] = append(result[*row.MenuID], row.RoleID)
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[int][]int), nil
+}
+
+// project 实时计算用户的生效权限投影行
+//
+// 超级管理员只记录一行标记；其余用户每个直接绑定的角色对每项授予且未被拒绝的权限记一行 allow，
+// 对每项拒绝的权限记一行 deny。
+func (r *PermissionResolver) project(user models.User, hierarchy *permissionHierarchy) ([]models.UserEffectivePermission, error) {
+	superRoleID, err := r.superRoleID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if superRoleID != 0 {
+		return []models.UserEffectivePermission{{
+			TenantID: user.TenantID,
+			UserID:   user.ID,
+			RoleID:   superRoleID,
+			Effect:   models.EffectiveSuperAdmin,
+		}}, nil
+	}
+
+	grants, err := r.resolve(user.ID, hierarchy)
+	if err != nil || grants == nil {
+		return nil, err
+	}
+
+	var rows []models.UserEffectivePermission
+	add := func(permissionID, roleID int, effect string) {
+		permission := hierarchy.permissions[permissionID]
+		rows = append(rows, models.UserEffectivePermission{
+			TenantID:     user.TenantID,
+			UserID:       user.ID,
+			PermissionID: permissionID,
+			RoleID:       roleID,
+			Effect:       effect,
+			Code:         permission.Code,
+			Type:         permission.Type,
+			MenuID:       permission.MenuID,
+		})
+	}
+	for roleID, granted := range grants.byRole {
+		for id := range granted {
+			if len(grants.denied[id]) == 0 {
+				add(id, roleID, models.EffectiveAllow)
+			}
+		}
+	}
+	for id, roleIDs := range grants.denied {
+		for _, roleID := range roleIDs {
+			add(id, roleID, models.EffectiveDeny)
+		}
+	}
+	sortProjection(rows)
+	return rows, nil
+}
+
+// refreshUsers 重新计算用户的生效权限投影，已删除或不存在的用户只清除其投影
+//
+// 在修改绑定关系的事务中调用，r 需通过 withDB 绑定该事务；用户须属于当前租户。
+func (r *PermissionResolver) refreshUsers(userIDs []int) error {
+	userIDs = uniqueInts(userIDs)
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	if err := r.db.Where("user_id IN ?", userIDs).Delete(&models.UserEffectivePermission{}).Error; err != nil {
+		return err
+	}
+
+	var users []models.User
+	if err := r.db.Select("id, tenant_id").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	hierarchy, err := loadPermissionHierarchy(r.db)
+	if err != nil {
+		return err
+	}
+
+	var rows []models.UserEffectivePermission
+	for _, user := range users {
+		projected, err := r.project(user, hierarchy)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, projected...)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(&rows, projectionBatchSize).Error
+}
+
+// refreshRoles 重新计算直接或通过继承拥有这些角色的用户的生效权限投影
+func (r *PermissionResolver) refreshRoles(roleIDs []int) error {
+	userIDs, err := roleUsers(r.db, roleIDs)
+	if err != nil {
+		return err
+	}
+	return r.refreshUsers(userIDs)
+}
+
+// refreshCreatedPermission 新建权限后重新计算可能因它改变生效权限的用户
+//
+// 新权限还没有角色直接绑定，也没有子权限，只会经由匹配它的通配权限，或 subtree 模式下的祖先权限生效，
+// 因此只需刷新绑定了这些权限（授予或拒绝）的角色。
+func (r *PermissionResolver) refreshCreatedPermission(permission *models.Permission) error {
+	var wildcards []models.Permission
+	if err := r.db.Select("id, code").Where("code LIKE ? AND id <> ?", "%*%", permission.ID).Find(&wildcards).Error; err != nil {
+		return err
+	}
+	var permissionIDs []int
+	for _, wildcard := range wildcards {
+		if matchPermissionCode(wildcard.Code, permission.Code) {
+			permissionIDs = append(permissionIDs, wildcard.ID)
+		}
+	}
+
+	if r.grantMode == config.GrantModeSubtree {
+		visited := make(map[int]bool)
+		for id := permissionParentID(permission.ParentID); id != 0 && !visited[id]; {
+			visited[id] = true
+			permissionIDs = append(permissionIDs, id)
+
+			var parent models.Permission
+			if err := r.db.Select("id, parent_id").Where("id = ?", id).Take(&parent).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					break
+				}
+				return err
+			}
+			id = permissionParentID(parent.ParentID)
+		}
+	}
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+
+	var roleIDs []int
+	if err := r.db.Table("role_permission").Where("permission_id IN ?", permissionIDs).Distinct().Pluck("role_id", &roleIDs).Error; err != nil {
+		return err
+	}
+	return r.refreshRoles(roleIDs)
+}
+
+// refreshTenant 重新计算当前租户全部用户的生效权限投影
+//
+// 修改、删除权限会改变通配权限和层级权限的展开范围，无法只定位到个别用户。
+func (r *PermissionResolver) refreshTenant() error {
+	// 没有租户上下文时下面的删除会波及全部租户
+	if _, ok := reqctx.TenantID(r.db.Statement.Context); !ok {
+		return errors.New("刷新租户的生效权限需要租户上下文")
+	}
+	if err := r.db.Where("1 = 1").Delete(&models.UserEffectivePermission{}).Error; err != nil {
+		return err
+	}
+	var userIDs []int
+	if err := r.db.Model(&models.User{}).Pluck("id", &userIDs).Error; err != nil {
+		return err
+	}
+	return r.refreshUsers(userIDs)
+}
+
+// withDB 返回使用指定数据库连接（通常为事务）的解析器实例，该实例不读写缓存
+func (r *PermissionResolver) withDB(db *gorm.DB) *PermissionResolver {
+	clone := *r
+	clone.db = db
+	clone.cache = nil
+	return &clone
+}
+
+// roleUsers 返回直接绑定这些角色或其任一子孙角色的用户ID
+func roleUsers(db *gorm.DB, roleIDs []int) ([]int, error) {
+	roles := make(map[int]bool, len(roleIDs))
+	frontier := make([]int, 0, len(roleIDs))
+	for _, id := range roleIDs {
+		if !roles[id] {
+			roles[id] = true
+			frontier = append(frontier, id)
+		}
+	}
+	// 沿继承关系向下查找继承了这些角色的子孙角色
+	for len(frontier) > 0 {
+		var children []int
+		if err := db.Table("role_inheritance").Where("parent_role_id IN ?", frontier).Pluck("role_id", &children).Error; err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, id := range children {
+			if !roles[id] {
+				roles[id] = true
+				frontier = append(frontier, id)
+			}
+		}
+	}
+
+	all := make([]int, 0, len(roles))
+	for id := range roles {
+		all = append(all, id)
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	var userIDs []int
+	if err := db.Table("user_role").Where("role_id IN ?", all).Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// sortProjection 按用户、权限、角色排序投影行，便于比较和批量写入
+func sortProjection(rows []models.UserEffectivePermission) {
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.UserID != b.UserID {
+			return a.UserID < b.UserID
+		}
+		if a.PermissionID != b.PermissionID {
+			return a.PermissionID < b.PermissionID
+		}
+		return a.RoleID < b.RoleID
+	})
+}
+
+// ProjectionMismatch 生效权限投影与实时计算结果不一致的用户
+type ProjectionMismatch struct {
+	TenantID   int      `json:"tenant_id"`
+	UserID     int      `json:"user_id"`
+	Missing    []string `json:"missing"`    // 实时计算有而投影中没有的行
+	Unexpected []string `json:"unexpected"` // 投影中有而实时计算没有的行
+}
+
+// PermissionProjectionService 生效权限投影的全量重建和一致性检查，供运维命令使用
+type PermissionProjectionService struct {
+	db       *gorm.DB
+	resolver *PermissionResolver
+}
+
+// NewPermissionProjectionService 创建生效权限投影服务实例
+func NewPermissionProjectionService(db *gorm.DB, cfg *config.Config) *PermissionProjectionService {
+	return &PermissionProjectionService{db: db, resolver: NewPermissionResolver(db, cfg)}
+}
+
+// Rebuild 逐个租户全量重建生效权限投影，每个租户在单独的事务中完成
+func (s *PermissionProjectionService) Rebuild(ctx context.Context) error {
+	tenantIDs, err := s.tenantIDs(ctx)
+	if err != nil {
+		return err
+	}
+	// 清除已不存在的租户遗留的投影
+	if err := s.db.WithContext(ctx).Where("tenant_id NOT IN ?", tenantIDs).Delete(&models.UserEffectivePermission{}).Error; err != nil {
+		return err
+	}
+	for _, tenantID := range tenantIDs {
+		tenantCtx := reqctx.WithTenantID(ctx, tenantID)
+		err := s.db.WithContext(tenantCtx).Transaction(func(tx *gorm.DB) error {
+			return s.resolver.withDB(tx).refreshTenant()
+		})
+		if err != nil {
+			return fmt.Errorf("重建租户 %d 的生效权限失败: %w", tenantID, err)
+		}
+	}
+	// 通知运行中的实例清空权限缓存
+	return bumpPolicyVersion(s.db.WithContext(ctx))
+}
+
+// RebuildIfEmpty 投影表为空时全量重建，用于升级后首次启动
+func (s *PermissionProjectionService) RebuildIfEmpty(ctx context.Context) (bool, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.UserEffectivePermission{}).Limit(1).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+	return true, s.Rebuild(ctx)
+}
+
+// Check 逐个用户对比生效权限投影与实时计算的结果，返回不一致的用户
+func (s *PermissionProjectionService) Check(ctx context.Context) ([]ProjectionMismatch, error) {
+	tenantIDs, err := s.tenantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := make([]ProjectionMismatch, 0)
+	for _, tenantID := range tenantIDs {
+		db := s.db.WithContext(reqctx.WithTenantID(ctx, tenantID))
+		resolver := s.resolver.withDB(db)
+
+		var actual []models.UserEffectivePermission
+		if err := db.Find(&actual).Error; err != nil {
+			return nil, err
+		}
+		actualByUser := make(map[int][]models.UserEffectivePermission)
+		for _, row := range actual {
+			actualByUser[row.UserID] = append(actualByUser[row.UserID], row)
+		}
+
+		var users []models.User
+		if err := db.Select("id, tenant_id").Order("id").Find(&users).Error; err != nil {
+			return nil, err
+		}
+		hierarchy, err := loadPermissionHierarchy(db)
+		if err != nil {
+			return nil, err
+		}
+
+		checked := make(map[int]bool, len(users))
+		for _, user := range users {
+			checked[user.ID] = true
+			expected, err := resolver.project(user, hierarchy)
+			if err != nil {
+				return nil, err
+			}
+			if mismatch := diffProjection(tenantID, user.ID, expected, actualByUser[user.ID]); mismatch != nil {
+				mismatches = append(mismatches, *mismatch)
+			}
+		}
+		// 已删除的用户不应再有投影
+		for userID, rows := range actualByUser {
+			if !checked[userID] {
+				mismatches = append(mismatches, *diffProjection(tenantID, userID, nil, rows))
+			}
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		if mismatches[i].TenantID != mismatches[j].TenantID {
+			return mismatches[i].TenantID < mismatches[j].TenantID
+		}
+		return mismatches[i].UserID < mismatches[j].UserID
+	})
+	return mismatches, nil
+}
+
+// tenantIDs 返回全部租户ID
+func (s *PermissionProjectionService) tenantIDs(ctx context.Context) ([]int, error) {
+	var tenantIDs []int
+	if err := s.db.WithContext(ctx).Model(&models.Tenant{}).Order("id").Pluck("id", &tenantIDs).Error; err != nil {
+		return nil, err
+	}
+	return tenantIDs, nil
+}
+
+// diffProjection 比较用户预期和实际的投影行，一致时返回 nil
+func diffProjection(tenantID, userID int, expected, actual []models.UserEffectivePermission) *ProjectionMismatch {
+	key := func(row models.UserEffectivePermission) string {
+		menuID := 0
+		if row.MenuID != nil {
+			menuID = *row.MenuID
+		}
+		return fmt.Sprintf("permission=%d role=%d effect=%s code=%s type=%s menu=%d",
+			row.PermissionID, row.RoleID, row.Effect, row.Code, row.Type, menuID)
+	}
+
+	want := make(map[string]bool, len(expected))
+	for _, row := range expected {
+		want[key(row)] = true
+	}
+	have := make(map[string]bool, len(actual))
+	for _, row := range actual {
+		have[key(row)] = true
+	}
+
+	mismatch := &ProjectionMismatch{TenantID: tenantID, UserID: userID, Missing: make([]string, 0), Unexpected: make([]string, 0)}
+	for k := range want {
+		if !have[k] {
+			mismatch.Missing = append(mismatch.Missing, k)
+		}
+	}
+	for k := range have {
+		if !want[k] {
+			mismatch.Unexpected = append(mismatch.Unexpected, k)
+		}
+	}
+	if len(mismatch.Missing) == 0 && len(mismatch.Unexpected) == 0 {
+		return nil
+	}
+	sort.Strings(mismatch.Missing)
+	sort.Strings(mismatch.Unexpected)
+	return mismatch
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"reflect"
+	"tenant-center/models"
+	"testing"
+)
+
+func TestDiffProjection(t *testing.T) {
+	menuID := 5
+	allow := models.UserEffectivePermission{PermissionID: 1, RoleID: 2, Effect: models.EffectiveAllow, Code: "user:create", Type: "button"}
+	deny := models.UserEffectivePermission{PermissionID: 3, RoleID: 2, Effect: models.EffectiveDeny, Code: "user:delete", Type: "button"}
+	menu := models.UserEffectivePermission{PermissionID: 4, RoleID: 2, Effect: models.EffectiveAllow, Code: "menu:user", Type: "menu", MenuID: &menuID}
+
+	allowKey := "permission=1 role=2 effect=allow code=user:create type=button menu=0"
+	denyKey := "permission=3 role=2 effect=deny code=user:delete type=button menu=0"
+	menuKey := "permission=4 role=2 effect=allow code=menu:user type=menu menu=5"
+
+	withEffect := func(row models.UserEffectivePermission, effect string) models.UserEffectivePermission {
+		row.Effect = effect
+		return row
+	}
+	withMenu := func(row models.UserEffectivePermission, menuID *int) models.UserEffectivePermission {
+		row.MenuID = menuID
+		return row
+	}
+
+	tests := []struct {
+		name             string
+		expected, actual []models.UserEffectivePermission
+		wantMissing      []string
+		wantUnexpected   []string
+	}{
+		{"both empty", nil, nil, nil, nil},
+		{"same rows in different order", []models.UserEffectivePermission{allow, deny, menu}, []models.UserEffectivePermission{menu, allow, deny}, nil, nil},
+		{"duplicate actual rows", []models.UserEffectivePermission{allow}, []models.UserEffectivePermission{allow, allow}, nil, nil},
+		{"missing row", []models.UserEffectivePermission{allow, deny}, []models.UserEffectivePermission{allow}, []string{denyKey}, []string{}},
+		{"unexpected row", []models.UserEffectivePermission{allow}, []models.UserEffectivePermission{allow, menu}, []string{}, []string{menuKey}},
+		{"deleted user", nil, []models.UserEffectivePermission{menu, allow}, []string{}, []string{allowKey, menuKey}},
+		{"effect changed", []models.UserEffectivePermission{withEffect(allow, models.EffectiveDeny)}, []models.UserEffectivePermission{allow},
+			[]string{"permission=1 role=2 effect=deny code=user:create type=button menu=0"}, []string{allowKey}},
+		{"menu cleared", []models.UserEffectivePermission{withMenu(menu, nil)}, []models.UserEffectivePermission{menu},
+			[]string{"permission=4 role=2 effect=allow code=menu:user type=menu menu=0"}, []string{menuKey}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffProjection(1, 7, tt.expected, tt.actual)
+			if tt.wantMissing == nil && tt.wantUnexpected == nil {
+				if got != nil {
+					t.Fatalf("diffProjection = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("diffProjection = nil, want mismatch")
+			}
+			if got.TenantID != 1 || got.UserID != 7 {
+				t.Errorf("mismatch tenant/user = %d/%d, want 1/7", got.TenantID, got.UserID)
+			}
+			if !reflect.DeepEqual(got.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", got.Missing, tt.wantMissing)
+			}
+			if !reflect.DeepEqual(got.Unexpected, tt.wantUnexpected) {
+				t.Errorf("Unexpected = %v, want %v", got.Unexpected, tt.wantUnexpected)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"tenant-center/config"
+	"tenant-center/models"
+)
+
+// PermissionResolver 权限解析器，按 user_role → 角色及其继承的祖先角色 → role_permission → permission 计算用户的有效权限
+//
+// 授予模式不是 exact 时，直接授予的权限按权限层级展开后再参与计算。
+// 任一角色显式拒绝的权限不生效，即使其他角色授予了该权限。
+// 计算结果物化在 user_effective_permission 表中，权限判定和菜单授权直接查询该表，
+// 绑定关系和权限变更时在同一事务中调用 refresh 系列方法刷新受影响的用户。
+// 数据库注册了 PolicyCache 插件时，查询投影的结果还会缓存在进程内。
+type PermissionResolver struct {
+	db        *gorm.DB
+	superRole string
+	grantMode string
+	cache     *PolicyCache
+}
+
+// NewPermissionResolver 创建权限解析器实例
+func NewPermissionResolver(db *gorm.DB, cfg *config.Config) *PermissionResolver {
+	cache, _ := db.Config.Plugins[policyCacheName].(*PolicyCache)
+	return &PermissionResolver{
+		db:        db,
+		superRole: cfg.Authz.SuperRole,
+		grantMode: cfg.Authz.GrantMode,
+		cache:     cache,
+	}
+}
+
+// WithContext 返回绑定请求上下文的解析器实例
+func (r *PermissionResolver) WithContext(ctx context.Context) *PermissionResolver {
+	clone := *r
+	clone.db = r.db.WithContext(ctx)
+	return &clone
+}
+
+// userRoles 返回用户直接绑定的角色 → 该角色自身及其继承的全部祖先角色
+func (r *PermissionResolver) userRoles(userID int) (map[int][]int, error) {
+	var direct []int
+	if err := r.db.Table("user_role").Where("user_id = ?", userID).Order("role_id").Pluck("role_id", &direct).Error; err != nil {
+		return nil, err
+	}
+
+	parents, err := loadRoleParents(r.db, direct)
+	if err != nil {
+		return nil, err
+	}
+
+	closure := make(map[int][]int, len(direct))
+	for _, roleID := range direct {
+		closure[roleID] = append([]int{roleID}, roleAncestors(parents, roleID)...)
+	}
+	return closure, nil
+}
+
+// effectiveRoleIDs 返回用户生效的全部角色ID，包括继承的祖先角色
+func (r *PermissionResolver) effectiveRoleIDs(userID int) ([]int, error) {
+	closure, err := r.userRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var roleIDs []int
+	for _, roles := range closure {
+		roleIDs = append(roleIDs, roles...)
+	}
+	return uniqueInts(roleIDs), nil
+}
+
+// superRoleID 按用户当前的角色实时计算其拥有的超级管理员角色ID，没有时返回 0
+func (r *PermissionResolver) superRoleID(userID int) (int, error) {
+	if r.superRole == "" {
+		return 0, nil
+	}
+
+	roleIDs, err := r.effectiveRoleIDs(userID)
+	if err != nil || len(roleIDs) == 0 {
+		return 0, err
+	}
+
+	var ids []int
+	if err := r.db.Model(&models.Role{}).
+		Where("id IN ? AND code = ?", roleIDs, r.superRole).
+		Limit(1).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
+// resolvedGrants 用户通过角色获得的授权结果
+type resolvedGrants struct {
+	hierarchy *permissionHierarchy
+	byRole    map[int]map[int]bool // 用户直接绑定的角色 → 该角色及其祖先角色授予的权限
+	denied    map[int][]int        // 被拒绝的权限 → 拒绝它的用户直接绑定的角色，拒绝优先于授予
+}
+
+// allowed 返回授予且未被拒绝的权限ID集合
+func (g *resolvedGrants) allowed() map[int]bool {
+	allowed := make(map[int]bool)
+	for _, granted := range g.byRole {
+		for id := range granted {
+			if len(g.denied[id]) == 0 {
+				allowed[id] = true
+			}
+		}
+	}
+	return allowed
+}
+
+// resolve 实时计算用户的授予和拒绝的权限，均按授予模式展开，用户没有角色时返回 nil
+//
+// hierarchy 为 nil 时加载当前租户的权限层级，批量计算多个用户时由调用方加载一次后传入。
+func (r *PermissionResolver) resolve(userID int, hierarchy *permissionHierarchy) (*resolvedGrants, error) {
+	closure, err := r.userRoles(userID)
+	if err != nil || len(closure) == 0 {
+		return nil, err
+	}
+
+	var roleIDs []int
+	for _, roles := range closure {
+		roleIDs = append(roleIDs, roles...)
+	}
+	grants, err := loadRoleGrants(r.db, uniqueInts(roleIDs))
+	if err != nil {
+		return nil, err
+	}
+	if hierarchy == nil {
+		if hierarchy, err = loadPermissionHierarchy(r.db); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &resolvedGrants{
+		hierarchy: hierarchy,
+		byRole:    make(map[int]map[int]bool, len(closure)),
+		denied:    make(map[int][]int),
+	}
+	for roleID, roles := range closure {
+		var allow, deny []int
+		for _, id := range roles {
+			allow = append(allow, grants.allow[id]...)
+			deny = append(deny, grants.deny[id]...)
+		}
+		result.byRole[roleID] = hierarchy.expand(allow, r.grantMode)
+		for id := range hierarchy.expandDeny(deny, r.grantMode) {
+			result.denied[id] = append(result.denied[id], roleID)
+		}
+	}
+	return result, nil
+}
+
+// 权限判定的原因
+const (
+	DecisionReasonSuperAdmin = "super_admin" // 拥有超级管理员角色
+	DecisionReasonGranted    = "granted"     // 角色授予了匹配的权限
+	DecisionReasonDenied     = "denied"      // 角色显式拒绝了匹配的权限
+	DecisionReasonNotGranted = "not_granted" // 没有任何角色授予匹配的权限
+)
+
+// Decision 权限判定结果
+type Decision struct {
+	Permission string `json:"permission" example:"user:create"`
+	Allowed    bool   `json:"allowed" example:"true"`
+	Reason     string `json:"reason" enums:"super_admin,granted,denied,not_granted" example:"granted"`
+	RoleID     int    `json:"role_id,omitempty" example:"2"` // 决定结果的角色：授予或拒绝该权限的用户角色，或超级管理员角色
+}
+
+// HasPermission 判断用户是否拥有指定权限
+func (r *PermissionResolver) HasPermission(userID int, code string) (bool, error) {
+	decisions, err := r.Decide(userID, []string{code})
+	if err != nil {
+		return false, err
+	}
+	return decisions[0].Allowed, nil
+}
+
+// decideLive 按用户当前的角色和权限实时判定，规则与 Decide 相同，用于解释授权路径和校验投影
+func (r *PermissionResolver) decideLive(userID int, codes []string) ([]Decision, error) {
+	decisions := make([]Decision, len(codes))
+	for i, code := range codes {
+		decisions[i] = Decision{Permission: code, Reason: DecisionReasonNotGranted}
+	}
+
+	superRoleID, err := r.superRoleID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if superRoleID != 0 {
+		for i := range decisions {
+			decisions[i].Allowed = true
+			decisions[i].Reason = DecisionReasonSuperAdmin
+			decisions[i].RoleID = superRoleID
+		}
+		return decisions, nil
+	}
+
+	grants, err := r.resolve(userID, nil)
+	if err != nil || grants == nil {
+		return decisions, err
+	}
+
+	granting := grants.granting()
+	for i := range decisions {
+		code := decisions[i].Permission
+		matches := func(permission models.Permission) bool {
+			return matchPermissionCode(permission.Code, code)
+		}
+		if roleID := grants.matchRole(grants.denied, matches); roleID != 0 {
+			decisions[i].Reason = DecisionReasonDenied
+			decisions[i].RoleID = roleID
+			continue
+		}
+		if roleID := grants.matchRole(granting, matches); roleID != 0 {
+			decisions[i].Allowed = true
+			decisions[i].Reason = DecisionReasonGranted
+			decisions[i].RoleID = roleID
+		}
+	}
+	return decisions, nil
+}
+
+// granting 返回 权限ID → 授予它的用户直接绑定的角色，不考虑拒绝
+func (g *resolvedGrants) granting() map[int][]int {
+	granting := make(map[int][]int)
+	for roleID, granted := range g.byRole {
+		for id := range granted {
+			granting[id] = append(granting[id], roleID)
+		}
+	}
+	return granting
+}
+
+// matchRole 在 权限ID → 角色ID 中查找满足条件的权限，返回其中最小的角色ID，没有匹配时返回 0
+func (g *resolvedGrants) matchRole(roles map[int][]int, matches func(models.Permission) bool) int {
+	matched := 0
+	for id, roleIDs := range roles {
+		if len(roleIDs) == 0 || !matches(g.hierarchy.permissions[id]) {
+			continue
+		}
+		for _, roleID := range roleIDs {
+			if matched == 0 || roleID < matched {
+				matched = roleID
+			}
+		}
+	}
+	return matched
+}
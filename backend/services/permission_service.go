@@ -1,26 +1,58 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"gorm.io/gorm"
+	"tenant-center/config"
 	"tenant-center/models"
 )
 
+// ErrPermissionHasChildren 权限存在子权限，不能直接删除
+var ErrPermissionHasChildren = errors.New("权限存在子权限，不能删除")
+
 // PermissionService 权限服务
 type PermissionService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	resolver *PermissionResolver
 }
 
 // NewPermissionService 创建权限服务实例
-func NewPermissionService(db *gorm.DB) *PermissionService {
-	return &PermissionService{db: db}
+func NewPermissionService(db *gorm.DB, cfg *config.Config) *PermissionService {
+	return &PermissionService{db: db, resolver: NewPermissionResolver(db, cfg)}
+}
+
+// WithContext 返回绑定请求上下文的服务实例，数据库操作按上下文中的租户自动隔离
+func (s *PermissionService) WithContext(ctx context.Context) *PermissionService {
+	return &PermissionService{db: s.db.WithContext(ctx), resolver: s.resolver.WithContext(ctx)}
 }
 
 // CreatePermission 创建权限
 func (s *PermissionService) CreatePermission(permission *models.Permission) error {
-	return s.db.Create(permission).Error
+	if err := validatePermissionCode(permission.Code); err != nil {
+		return err
+	}
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		if err := checkPermissionParent(tx, 0, permissionParentID(permission.ParentID)); err != nil {
+			return err
+		}
+		if err := checkPermissionOwner(tx, permission); err != nil {
+			return err
+		}
+		if err := tx.Create(permission).Error; err != nil {
+			return err
+		}
+		// 新权限可能落入已授予的通配权限或父权限的范围
+		if err := s.resolver.withDB(tx).refreshCreatedPermission(permission); err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourcePermission, permission.ID, models.AuditActionCreate, nil, permission)
+	})
 }
 
-// UpdatePermission 更新权限
+// UpdatePermission 更新权限，permission.Version 非 0 时校验版本，更新成功后写回新版本
+//
+// 只在编码变化时校验编码格式，不符合格式的存量编码保持不变时仍可修改其他字段。
 func (s *PermissionService) UpdatePermission(permission *models.Permission) error {
 	// 创建一个map来存储需要更新的字段
 	updates := map[string]interface{}{
@@ -29,10 +61,59 @@ func (s *PermissionService) UpdatePermission(permission *models.Permission) erro
 		"type":      permission.Type,
 		"menu_id":   permission.MenuID,
 		"button_id": permission.ButtonID,
+		"parent_id": permission.ParentID,
 	}
 
-	// 使用 Updates 方法只更新指定字段，让 GORM 自动处理时间戳
-	return s.db.Model(permission).Updates(updates).Error
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		var before models.Permission
+		if err := tx.First(&before, permission.ID).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(before.Version, permission.Version); err != nil {
+			return err
+		}
+		if permission.Code != before.Code {
+			if err := validatePermissionCode(permission.Code); err != nil {
+				return err
+			}
+		}
+		if err := checkPermissionParent(tx, permission.ID, permissionParentID(permission.ParentID)); err != nil {
+			return err
+		}
+		if err := checkPermissionOwner(tx, permission); err != nil {
+			return err
+		}
+
+		// 只更新指定字段并递增版本，让 GORM 自动处理时间戳
+		if err := updateVersioned(tx, permission, before.Version, updates); err != nil {
+			return err
+		}
+
+		var after models.Permission
+		if err := tx.First(&after, permission.ID).Error; err != nil {
+			return err
+		}
+		permission.Version = after.Version
+		if err := s.resolver.withDB(tx).refreshTenant(); err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourcePermission, permission.ID, models.AuditActionUpdate, &before, &after)
+	})
+}
+
+// checkPermissionOwner 校验权限所属的菜单、按钮存在于当前租户，避免引用其他租户的数据
+func checkPermissionOwner(tx *gorm.DB, permission *models.Permission) error {
+	if permission.MenuID != nil {
+		if err := checkIDExists(tx, &models.Menu{}, *permission.MenuID, ErrMenuNotFound); err != nil {
+			return err
+		}
+	}
+	if permission.ButtonID != nil {
+		if err := checkIDExists(tx, &models.Button{}, *permission.ButtonID, ErrButtonNotFound); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetPermissionByID 根据ID获取权限
@@ -44,23 +125,96 @@ func (s *PermissionService) GetPermissionByID(id int) (*models.Permission, error
 	return &permission, nil
 }
 
-// PagePermissions 获取权限列表
-func (s *PermissionService) PagePermissions(page, pageSize int) ([]models.Permission, int64, error) {
-	var permissions []models.Permission
-	var total int64
+// ExpandPermissionCode 返回通配编码当前匹配的具体权限，按编码排序，不含其他通配权限
+func (s *PermissionService) ExpandPermissionCode(pattern string) ([]models.Permission, error) {
+	if err := validatePermissionCode(pattern); err != nil {
+		return nil, err
+	}
+
+	var candidates []models.Permission
+	if err := s.db.Where("code LIKE ?", permissionCodeLike(pattern)).Order("code").Find(&candidates).Error; err != nil {
+		return nil, err
+	}
 
-	// 计算总记录数
-	if err := s.db.Model(&models.Permission{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+	// LIKE 的 % 会跨越冒号，需要按段再匹配一次
+	permissions := make([]models.Permission, 0, len(candidates))
+	for _, permission := range candidates {
+		if !isWildcardCode(permission.Code) && matchPermissionCode(pattern, permission.Code) {
+			permissions = append(permissions, permission)
+		}
 	}
+	return permissions, nil
+}
+
+// permissionListSpec 权限列表的搜索、筛选和排序规则
+var permissionListSpec = &listSpec{
+	keywordColumns: []string{"name", "code"},
+	filters: map[string]func(db *gorm.DB, value interface{}) *gorm.DB{
+		"type":      eqFilter("type"),
+		"menu_id":   eqFilter("menu_id"),
+		"button_id": eqFilter("button_id"),
+		"parent_id": eqFilter("parent_id"),
+		"role_id": func(db *gorm.DB, value interface{}) *gorm.DB {
+			return db.Where("id IN (SELECT permission_id FROM role_permission WHERE role_id = ?)", value)
+		},
+	},
+	sortColumns: commonSortColumns(map[string]string{"name": "name", "code": "code", "type": "type"}),
+	defaultSort: []string{"id"},
+}
 
-	// 获取分页数据
-	offset := (page - 1) * pageSize
-	if err := s.db.Offset(offset).Limit(pageSize).Find(&permissions).Error; err != nil {
-		return nil, 0, err
+// PagePermissions 获取权限列表，支持关键字搜索、字段筛选和多字段排序
+func (s *PermissionService) PagePermissions(query ListQuery) ([]models.Permission, PageInfo, error) {
+	var permissions []models.Permission
+	info, err := pageQuery(s.db.Model(&models.Permission{}), query, permissionListSpec, &permissions)
+	if err != nil {
+		return nil, PageInfo{}, err
 	}
+	return permissions, info, nil
+}
+
+// DeletePermission 将权限移入回收站，同时摘下角色权限关联，存在子权限时拒绝删除
+func (s *PermissionService) DeletePermission(permissionID int) error {
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		var permission models.Permission
+		if err := tx.First(&permission, permissionID).Error; err != nil {
+			return err
+		}
 
-	return permissions, total, nil
+		var count int64
+		if err := tx.Model(&models.Permission{}).Where("parent_id = ?", permissionID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrPermissionHasChildren
+		}
+
+		if err := deletePermissions(tx, []int{permissionID}); err != nil {
+			return err
+		}
+		if err := s.resolver.withDB(tx).refreshTenant(); err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourcePermission, permissionID, models.AuditActionDelete, &permission, nil)
+	})
+}
+
+// deletePermissions 软删除权限并摘下其角色关联，调用方需保证权限ID属于当前租户
+//
+// 未被一同删除的子权限会被提升为顶级权限，避免指向不存在的父级。
+func deletePermissions(tx *gorm.DB, permissionIDs []int) error {
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+
+	if err := detachBindings(tx, models.ResourcePermission, permissionIDs); err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Permission{}).
+		Where("parent_id IN ? AND id NOT IN ?", permissionIDs, permissionIDs).
+		Update("parent_id", nil).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.Permission{}, permissionIDs).Error
 }
 
 // GetPermissionsByType 根据类型获取权限列表
@@ -0,0 +1,571 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"tenant-center/config"
+	"tenant-center/models"
+	"time"
+)
+
+// 回收站相关错误
+var (
+	ErrUnknownResourceType = errors.New("未知的资源类型")
+	ErrParentNotRestored   = errors.New("上级记录不存在或仍在回收站中，请先恢复上级记录")
+	ErrRestoreDuplicate    = errors.New("已存在相同用户名或编码的记录，请先修改或删除该记录再恢复")
+)
+
+// RecycleBinItem 回收站中的记录
+type RecycleBinItem struct {
+	Type      string    `json:"type" example:"role"`
+	ID        int       `json:"id" example:"1"`
+	Name      string    `json:"name" example:"管理员"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// RecycleBinService 回收站服务，负责列出、恢复和彻底删除软删除的记录
+type RecycleBinService struct {
+	db       *gorm.DB
+	resolver *PermissionResolver
+}
+
+// NewRecycleBinService 创建回收站服务实例
+func NewRecycleBinService(db *gorm.DB, cfg *config.Config) *RecycleBinService {
+	return &RecycleBinService{db: db, resolver: NewPermissionResolver(db, cfg)}
+}
+
+// WithContext 返回绑定请求上下文的服务实例，数据库操作按上下文中的租户自动隔离
+func (s *RecycleBinService) WithContext(ctx context.Context) *RecycleBinService {
+	return &RecycleBinService{db: s.db.WithContext(ctx), resolver: s.resolver.WithContext(ctx)}
+}
+
+// PageDeleted 分页获取回收站中指定类型的记录，默认按删除时间倒序，游标分页按 (deleted_at, id) 排序
+func (s *RecycleBinService) PageDeleted(resourceType string, query ListQuery) ([]RecycleBinItem, PageInfo, error) {
+	model, err := newResourceModel(resourceType)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+
+	nameColumn := "name"
+	if resourceType == models.ResourceUser {
+		nameColumn = "username"
+	}
+	spec := &listSpec{
+		keywordColumns: []string{nameColumn},
+		sortColumns:    map[string]string{"id": "id", "deleted_at": "deleted_at"},
+		defaultSort:    []string{"-deleted_at", "-id"},
+		cursorColumn:   "deleted_at",
+	}
+
+	items := make([]RecycleBinItem, 0)
+	db := s.db.Unscoped().Model(model).
+		Select("id, " + nameColumn + " AS name, deleted_at").
+		Where("deleted_at IS NOT NULL")
+	info, err := pageQuery(db, query, spec, &items)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	for i := range items {
+		items[i].Type = resourceType
+	}
+
+	return items, info, nil
+}
+
+// Restore 从回收站恢复记录，同一次删除操作中一并删除的子菜单、按钮、权限随之恢复，
+// 删除时摘下的用户角色、角色权限关联也会重新写回
+func (s *RecycleBinService) Restore(resourceType string, id int) error {
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		group, err := loadDeletedGroup(tx, resourceType, id)
+		if err != nil {
+			return err
+		}
+		if err := group.checkParent(tx); err != nil {
+			return err
+		}
+		if err := group.checkDuplicates(tx); err != nil {
+			return err
+		}
+
+		for _, part := range group.parts() {
+			if len(part.ids) == 0 {
+				continue
+			}
+			model, _ := newResourceModel(part.resourceType)
+			if err := tx.Unscoped().Model(model).Where("id IN ?", part.ids).Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+			if err := restoreBindings(tx, part.resourceType, part.ids); err != nil {
+				return err
+			}
+		}
+
+		// 恢复的用户、角色、权限及其关联都会改变生效权限
+		if err := s.resolver.withDB(tx).refreshTenant(); err != nil {
+			return err
+		}
+		return recordAudit(tx, resourceType, id, models.AuditActionRestore, nil, group.snapshot())
+	})
+}
+
+// Purge 彻底删除回收站中的记录，同一次删除操作中一并删除的记录随之彻底删除
+func (s *RecycleBinService) Purge(resourceType string, id int) error {
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		group, err := loadDeletedGroup(tx, resourceType, id)
+		if err != nil {
+			return err
+		}
+
+		for _, part := range group.parts() {
+			if len(part.ids) == 0 {
+				continue
+			}
+			if err := purgeBindings(tx, part.resourceType, part.ids); err != nil {
+				return err
+			}
+			model, _ := newResourceModel(part.resourceType)
+			if err := tx.Unscoped().Delete(model, part.ids).Error; err != nil {
+				return err
+			}
+		}
+
+		if resourceType == models.ResourceUser {
+			if err := tx.Where("user_id = ?", id).Delete(&models.RefreshToken{}).Error; err != nil {
+				return err
+			}
+		}
+
+		return recordAudit(tx, resourceType, id, models.AuditActionPurge, group.snapshot(), nil)
+	})
+}
+
+// deletedGroup 同一次删除操作中软删除的一组记录
+type deletedGroup struct {
+	resourceType  string
+	id            int
+	record        interface{} // 回收站中的主记录
+	parentID      int         // 菜单的上级菜单、按钮所属菜单或权限所属的菜单/按钮
+	parentType    string
+	menuIDs       []int
+	buttonIDs     []int
+	permissionIDs []int
+	userIDs       []int
+	roleIDs       []int
+}
+
+// groupPart 一组记录中某一类资源的ID
+type groupPart struct {
+	resourceType string
+	ids          []int
+}
+
+// parts 按恢复顺序返回各类资源，上级资源在前
+func (g *deletedGroup) parts() []groupPart {
+	return []groupPart{
+		{models.ResourceUser, g.userIDs},
+		{models.ResourceRole, g.roleIDs},
+		{models.ResourceMenu, g.menuIDs},
+		{models.ResourceButton, g.buttonIDs},
+		{models.ResourcePermission, g.permissionIDs},
+	}
+}
+
+// snapshot 返回审计快照，下级记录只记录一同处理的部分
+func (g *deletedGroup) snapshot() *cascadeSnapshot {
+	snapshot := &cascadeSnapshot{Record: g.record}
+	switch g.resourceType {
+	case models.ResourceMenu:
+		snapshot.MenuIDs = g.menuIDs[1:]
+		snapshot.ButtonIDs = g.buttonIDs
+		snapshot.PermissionIDs = g.permissionIDs
+	case models.ResourceButton:
+		snapshot.PermissionIDs = g.permissionIDs
+	}
+	return snapshot
+}
+
+// checkParent 检查上级记录是否仍然存在且未被删除
+func (g *deletedGroup) checkParent(tx *gorm.DB) error {
+	if g.parentID == 0 {
+		return nil
+	}
+	state, err := resourceState(tx, g.parentType, g.parentID)
+	if err != nil {
+		return err
+	}
+	if state != resourceAlive {
+		return ErrParentNotRestored
+	}
+	return nil
+}
+
+// checkDuplicates 检查待恢复的用户、角色、权限是否与未删除的记录重名
+//
+// 回收站中的记录不占用唯一键，删除后可能已有同名记录被创建，直接恢复会违反唯一索引。
+func (g *deletedGroup) checkDuplicates(tx *gorm.DB) error {
+	if len(g.userIDs) > 0 {
+		var usernames []string
+		if err := tx.Unscoped().Model(&models.User{}).Where("id IN ?", g.userIDs).Pluck("username", &usernames).Error; err != nil {
+			return err
+		}
+		// 用户名全局唯一，使用原生SQL跳过租户隔离
+		var count int64
+		if err := tx.Raw("SELECT COUNT(*) FROM user WHERE username IN ? AND deleted_at IS NULL", usernames).Scan(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrRestoreDuplicate
+		}
+	}
+
+	for _, part := range []groupPart{
+		{models.ResourceRole, g.roleIDs},
+		{models.ResourcePermission, g.permissionIDs},
+	} {
+		if len(part.ids) == 0 {
+			continue
+		}
+		model, _ := newResourceModel(part.resourceType)
+		var codes []string
+		if err := tx.Unscoped().Model(model).Where("id IN ?", part.ids).Pluck("code", &codes).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(model).Where("code IN ?", codes).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrRestoreDuplicate
+		}
+	}
+	return nil
+}
+
+// loadDeletedGroup 加载回收站中的记录，以及与它在同一次删除操作中被删除的下级记录
+//
+// 同一次删除操作使用相同的 deleted_at（见 deleteSession），据此区分一同删除的记录和之前单独删除的记录。
+func loadDeletedGroup(tx *gorm.DB, resourceType string, id int) (*deletedGroup, error) {
+	group := &deletedGroup{resourceType: resourceType, id: id}
+	deleted := tx.Unscoped().Where("deleted_at IS NOT NULL")
+
+	switch resourceType {
+	case models.ResourceUser:
+		var user models.User
+		if err := deleted.First(&user, id).Error; err != nil {
+			return nil, err
+		}
+		group.record = &user
+		group.userIDs = []int{id}
+
+	case models.ResourceRole:
+		var role models.Role
+		if err := deleted.First(&role, id).Error; err != nil {
+			return nil, err
+		}
+		group.record = &role
+		group.roleIDs = []int{id}
+
+	case models.ResourcePermission:
+		var permission models.Permission
+		if err := deleted.First(&permission, id).Error; err != nil {
+			return nil, err
+		}
+		group.record = &permission
+		group.permissionIDs = []int{id}
+		switch {
+		case permission.ButtonID != nil:
+			group.parentType, group.parentID = models.ResourceButton, *permission.ButtonID
+		case permission.MenuID != nil:
+			group.parentType, group.parentID = models.ResourceMenu, *permission.MenuID
+		case permission.ParentID != nil && *permission.ParentID != 0:
+			group.parentType, group.parentID = models.ResourcePermission, *permission.ParentID
+		}
+
+	case models.ResourceButton:
+		var button models.Button
+		if err := deleted.First(&button, id).Error; err != nil {
+			return nil, err
+		}
+		group.record = &button
+		group.buttonIDs = []int{id}
+		group.parentType, group.parentID = models.ResourceMenu, button.MenuID
+
+		if err := tx.Unscoped().Model(&models.Permission{}).
+			Where("button_id = ? AND deleted_at = ?", id, button.DeletedAt).
+			Pluck("id", &group.permissionIDs).Error; err != nil {
+			return nil, err
+		}
+
+	case models.ResourceMenu:
+		var menu models.Menu
+		if err := deleted.First(&menu, id).Error; err != nil {
+			return nil, err
+		}
+		group.record = &menu
+		if menu.ParentID != nil && *menu.ParentID != 0 {
+			group.parentType, group.parentID = models.ResourceMenu, *menu.ParentID
+		}
+
+		// 同一时间删除的子孙菜单
+		group.menuIDs = []int{id}
+		visited := map[int]bool{id: true}
+		frontier := []int{id}
+		for len(frontier) > 0 {
+			var children []int
+			if err := tx.Unscoped().Model(&models.Menu{}).
+				Where("parent_id IN ? AND deleted_at = ?", frontier, menu.DeletedAt).
+				Pluck("id", &children).Error; err != nil {
+				return nil, err
+			}
+			frontier = frontier[:0]
+			for _, child := range children {
+				if !visited[child] {
+					visited[child] = true
+					group.menuIDs = append(group.menuIDs, child)
+					frontier = append(frontier, child)
+				}
+			}
+		}
+
+		if err := tx.Unscoped().Model(&models.Button{}).
+			Where("menu_id IN ? AND deleted_at = ?", group.menuIDs, menu.DeletedAt).
+			Pluck("id", &group.buttonIDs).Error; err != nil {
+			return nil, err
+		}
+
+		owned := tx.Where("menu_id IN ?", group.menuIDs)
+		if len(group.buttonIDs) > 0 {
+			owned = owned.Or("button_id IN ?", group.buttonIDs)
+		}
+		if err := tx.Unscoped().Model(&models.Permission{}).
+			Where(owned).Where("deleted_at = ?", menu.DeletedAt).
+			Pluck("id", &group.permissionIDs).Error; err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, ErrUnknownResourceType
+	}
+
+	return group, nil
+}
+
+// deleteSession 返回固定当前时间的会话，使同一次删除操作中软删除的记录具有相同的 deleted_at
+func deleteSession(tx *gorm.DB) *gorm.DB {
+	now := tx.NowFunc()
+	return tx.Session(&gorm.Session{NowFunc: func() time.Time { return now }})
+}
+
+// newResourceModel 根据资源类型返回对应的模型
+func newResourceModel(resourceType string) (interface{}, error) {
+	switch resourceType {
+	case models.ResourceUser:
+		return &models.User{}, nil
+	case models.ResourceRole:
+		return &models.Role{}, nil
+	case models.ResourcePermission:
+		return &models.Permission{}, nil
+	case models.ResourceMenu:
+		return &models.Menu{}, nil
+	case models.ResourceButton:
+		return &models.Button{}, nil
+	}
+	return nil, ErrUnknownResourceType
+}
+
+// 资源状态
+const (
+	resourceMissing = iota
+	resourceAlive
+	resourceDeleted
+)
+
+// resourceState 查询资源是否存在、是否在回收站中
+func resourceState(tx *gorm.DB, resourceType string, id int) (int, error) {
+	model, err := newResourceModel(resourceType)
+	if err != nil {
+		return resourceMissing, err
+	}
+
+	var row struct {
+		DeletedAt gorm.DeletedAt
+	}
+	if err := tx.Unscoped().Model(model).Select("deleted_at").Where("id = ?", id).Take(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return resourceMissing, nil
+		}
+		return resourceMissing, err
+	}
+	if row.DeletedAt.Valid {
+		return resourceDeleted, nil
+	}
+	return resourceAlive, nil
+}
+
+// joinTable 关联表结构
+type joinTable struct {
+	name        string
+	leftColumn  string
+	rightColumn string
+	leftType    string
+	rightType   string
+	hasEffect   bool // 关联行带有 effect 列
+}
+
+var joinTables = []joinTable{
+	{"user_role", "user_id", "role_id", models.ResourceUser, models.ResourceRole, false},
+	{"role_permission", "role_id", "permission_id", models.ResourceRole, models.ResourcePermission, true},
+	{"role_inheritance", "role_id", "parent_role_id", models.ResourceRole, models.ResourceRole, false},
+}
+
+// columnsFor 返回资源在关联表中所在的列，角色继承表两侧都是角色
+func (jt joinTable) columnsFor(resourceType string) []string {
+	var columns []string
+	if resourceType == jt.leftType {
+		columns = append(columns, jt.leftColumn)
+	}
+	if resourceType == jt.rightType {
+		columns = append(columns, jt.rightColumn)
+	}
+	return columns
+}
+
+// detachBindings 将资源在关联表中的关联行移入 deleted_binding，供恢复时写回
+func detachBindings(tx *gorm.DB, resourceType string, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	for _, jt := range joinTables {
+		for _, column := range jt.columnsFor(resourceType) {
+			if err := detachColumn(tx, jt, column, resourceType, ids); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// detachColumn 将关联表中指定列属于这些资源的关联行移入 deleted_binding
+func detachColumn(tx *gorm.DB, jt joinTable, column, resourceType string, ids []int) error {
+	var rows []struct {
+		LeftID  int
+		RightID int
+		Effect  string
+	}
+	selects := fmt.Sprintf("%s AS left_id, %s AS right_id", jt.leftColumn, jt.rightColumn)
+	if jt.hasEffect {
+		selects += ", effect"
+	}
+	if err := tx.Raw(fmt.Sprintf("SELECT %s FROM %s WHERE %s IN ?", selects, jt.name, column), ids).Scan(&rows).Error; err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	bindings := make([]models.DeletedBinding, 0, len(rows))
+	for _, row := range rows {
+		ownerID := row.LeftID
+		if column == jt.rightColumn {
+			ownerID = row.RightID
+		}
+		bindings = append(bindings, models.DeletedBinding{
+			ResourceType: resourceType,
+			ResourceID:   ownerID,
+			JoinTable:    jt.name,
+			LeftID:       row.LeftID,
+			RightID:      row.RightID,
+			Effect:       row.Effect,
+		})
+	}
+	if err := tx.Create(&bindings).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s IN ?", jt.name, column), ids).Error
+}
+
+// restoreBindings 将资源删除时摘下的关联写回关联表
+//
+// 关联另一方仍在回收站中时，关联改为挂在另一方名下，待其恢复时再写回；另一方已被彻底删除时丢弃。
+func restoreBindings(tx *gorm.DB, resourceType string, ids []int) error {
+	var bindings []models.DeletedBinding
+	if err := tx.Where("resource_type = ? AND resource_id IN ?", resourceType, ids).Find(&bindings).Error; err != nil {
+		return err
+	}
+
+	for _, binding := range bindings {
+		var jt joinTable
+		for _, t := range joinTables {
+			if t.name == binding.JoinTable {
+				jt = t
+			}
+		}
+
+		// 保存关联的资源在左侧时另一方在右侧，反之亦然
+		otherType, otherID := jt.rightType, binding.RightID
+		if binding.ResourceType != jt.leftType || binding.LeftID != binding.ResourceID {
+			otherType, otherID = jt.leftType, binding.LeftID
+		}
+
+		state, err := resourceState(tx, otherType, otherID)
+		if err != nil {
+			return err
+		}
+
+		switch state {
+		case resourceDeleted:
+			if err := tx.Model(&binding).Updates(map[string]interface{}{
+				"resource_type": otherType,
+				"resource_id":   otherID,
+			}).Error; err != nil {
+				return err
+			}
+			continue
+		case resourceAlive:
+			var count int64
+			if err := tx.Table(jt.name).
+				Where(fmt.Sprintf("%s = ? AND %s = ?", jt.leftColumn, jt.rightColumn), binding.LeftID, binding.RightID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				var err error
+				if jt.hasEffect && binding.Effect != "" {
+					err = tx.Exec(fmt.Sprintf("INSERT INTO %s (%s, %s, effect) VALUES (?, ?, ?)", jt.name, jt.leftColumn, jt.rightColumn),
+						binding.LeftID, binding.RightID, binding.Effect).Error
+				} else {
+					err = tx.Exec(fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (?, ?)", jt.name, jt.leftColumn, jt.rightColumn),
+						binding.LeftID, binding.RightID).Error
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Delete(&binding).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeBindings 清理资源保存的关联
+func purgeBindings(tx *gorm.DB, resourceType string, ids []int) error {
+	if err := tx.Where("resource_type = ? AND resource_id IN ?", resourceType, ids).Delete(&models.DeletedBinding{}).Error; err != nil {
+		return err
+	}
+
+	for _, jt := range joinTables {
+		for _, column := range jt.columnsFor(resourceType) {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s IN ?", jt.name, column), ids).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
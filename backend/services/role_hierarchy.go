@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"tenant-center/models"
+)
+
+// 角色继承相关错误
+var (
+	ErrParentRoleNotFound = errors.New("父角色不存在")
+	ErrRoleCycle          = errors.New("角色继承关系不能形成环")
+)
+
+// roleEdge 角色继承关系，RoleID 继承 ParentRoleID 的权限
+type roleEdge struct {
+	RoleID       int
+	ParentRoleID int
+}
+
+// loadRoleParents 从给定角色出发逐层向上加载继承关系，返回 角色ID → 直接父角色ID
+//
+// role_inheritance 表不带租户信息，调用方需保证起始角色属于当前租户，父角色在绑定时已校验。
+func loadRoleParents(db *gorm.DB, roleIDs []int) (map[int][]int, error) {
+	parents := make(map[int][]int)
+	visited := make(map[int]bool)
+	frontier := make([]int, 0, len(roleIDs))
+	for _, id := range roleIDs {
+		if !visited[id] {
+			visited[id] = true
+			frontier = append(frontier, id)
+		}
+	}
+
+	for len(frontier) > 0 {
+		var edges []roleEdge
+		if err := db.Table("role_inheritance").
+			Select("role_id, parent_role_id").
+			Where("role_id IN ?", frontier).
+			Order("role_id, parent_role_id").
+			Scan(&edges).Error; err != nil {
+			return nil, err
+		}
+
+		frontier = frontier[:0]
+		for _, edge := range edges {
+			parents[edge.RoleID] = append(parents[edge.RoleID], edge.ParentRoleID)
+			if !visited[edge.ParentRoleID] {
+				visited[edge.ParentRoleID] = true
+				frontier = append(frontier, edge.ParentRoleID)
+			}
+		}
+	}
+	return parents, nil
+}
+
+// roleAncestors 返回角色继承的全部祖先角色ID，不含自身，近的祖先在前
+func roleAncestors(parents map[int][]int, roleID int) []int {
+	var ancestors []int
+	visited := map[int]bool{roleID: true}
+	queue := []int{roleID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, parentID := range parents[id] {
+			if !visited[parentID] {
+				visited[parentID] = true
+				ancestors = append(ancestors, parentID)
+				queue = append(queue, parentID)
+			}
+		}
+	}
+	return ancestors
+}
+
+// rolePath 返回从角色沿继承关系到达祖先角色的最短路径，首尾包含两端，不可达时返回 nil
+func rolePath(parents map[int][]int, from, to int) []int {
+	previous := map[int]int{from: 0}
+	queue := []int{from}
+	for len(queue) > 0 && from != to {
+		id := queue[0]
+		queue = queue[1:]
+		for _, parentID := range parents[id] {
+			if _, ok := previous[parentID]; ok {
+				continue
+			}
+			previous[parentID] = id
+			if parentID == to {
+				queue = nil
+				break
+			}
+			queue = append(queue, parentID)
+		}
+	}
+	if _, ok := previous[to]; !ok {
+		return nil
+	}
+
+	var path []int
+	for id := to; id != from; id = previous[id] {
+		path = append(path, id)
+	}
+	path = append(path, from)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// roleGrant 角色直接绑定的权限及其授权效果
+type roleGrant struct {
+	RoleID       int
+	PermissionID int
+	Effect       string
+}
+
+// roleGrants 角色直接绑定的权限，按授权效果区分，均为 角色ID → 权限ID列表
+type roleGrants struct {
+	allow map[int][]int
+	deny  map[int][]int
+}
+
+// loadRoleGrants 加载角色直接绑定的权限
+func loadRoleGrants(db *gorm.DB, roleIDs []int) (*roleGrants, error) {
+	grants := &roleGrants{allow: make(map[int][]int), deny: make(map[int][]int)}
+	if len(roleIDs) == 0 {
+		return grants, nil
+	}
+
+	var rows []roleGrant
+	if err := db.Table("role_permission").
+		Select("role_id, permission_id, effect").
+		Where("role_id IN ?", roleIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if row.Effect == models.PermissionEffectDeny {
+			grants.deny[row.RoleID] = append(grants.deny[row.RoleID], row.PermissionID)
+		} else {
+			grants.allow[row.RoleID] = append(grants.allow[row.RoleID], row.PermissionID)
+		}
+	}
+	return grants, nil
+}
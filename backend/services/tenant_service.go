@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"tenant-center/config"
+	"tenant-center/models"
+	"tenant-center/reqctx"
+	"tenant-center/utils"
+)
+
+// TenantService 租户服务
+type TenantService struct {
+	db              *gorm.DB
+	superRole       string
+	passwordEncoder *utils.PasswordEncoder
+	resolver        *PermissionResolver
+}
+
+// NewTenantService 创建租户服务实例
+func NewTenantService(db *gorm.DB, cfg *config.Config) *TenantService {
+	// 算法名称已在配置校验阶段检查过
+	encoder, err := utils.NewPasswordEncoder(cfg.Password.Algorithm)
+	if err != nil {
+		encoder = utils.DefaultPasswordEncoder()
+	}
+
+	return &TenantService{
+		db:              db,
+		superRole:       cfg.Authz.SuperRole,
+		passwordEncoder: encoder,
+		resolver:        NewPermissionResolver(db, cfg),
+	}
+}
+
+// WithContext 返回绑定请求上下文的服务实例
+func (s *TenantService) WithContext(ctx context.Context) *TenantService {
+	clone := *s
+	clone.db = s.db.WithContext(ctx)
+	clone.resolver = s.resolver.WithContext(ctx)
+	return &clone
+}
+
+// CreateTenant 创建租户，adminUsername 不为空时同时在新租户下创建管理员账号及超级管理员角色
+func (s *TenantService) CreateTenant(tenant *models.Tenant, adminUsername, adminPassword string) error {
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		if err := tx.Create(tenant).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, models.ResourceTenant, tenant.ID, models.AuditActionCreate, nil, tenant); err != nil {
+			return err
+		}
+
+		if adminUsername == "" {
+			return nil
+		}
+
+		// 切换到新租户的上下文，后续创建的数据自动归属新租户
+		tenantTx := tx.WithContext(reqctx.WithTenantID(tx.Statement.Context, tenant.ID))
+
+		hashed, err := s.passwordEncoder.Encode(adminPassword)
+		if err != nil {
+			return err
+		}
+		admin := &models.User{Username: adminUsername, Password: hashed}
+		if err := tenantTx.Create(admin).Error; err != nil {
+			return err
+		}
+
+		if s.superRole == "" {
+			return nil
+		}
+		role := &models.Role{Name: "租户管理员", Code: s.superRole, Description: "租户初始化时创建的管理员角色"}
+		if err := tenantTx.Create(role).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("INSERT INTO user_role (user_id, role_id) VALUES (?, ?)", admin.ID, role.ID).Error; err != nil {
+			return err
+		}
+		return s.resolver.withDB(tenantTx).refreshUsers([]int{admin.ID})
+	})
+}
+
+// UpdateTenant 更新租户信息
+func (s *TenantService) UpdateTenant(tenant *models.Tenant) error {
+	if tenant.ID == models.DefaultTenantID && tenant.Status == models.TenantStatusDisabled {
+		return errors.New("默认租户不能停用")
+	}
+
+	// 创建一个map来存储需要更新的字段
+	updates := map[string]interface{}{
+		"name":   tenant.Name,
+		"status": tenant.Status,
+	}
+
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		var before models.Tenant
+		if err := tx.First(&before, tenant.ID).Error; err != nil {
+			return err
+		}
+
+		// 使用 Updates 方法只更新指定字段，让 GORM 自动处理时间戳
+		if err := tx.Model(tenant).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		var after models.Tenant
+		if err := tx.First(&after, tenant.ID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourceTenant, tenant.ID, models.AuditActionUpdate, &before, &after)
+	})
+}
+
+// GetTenantByID 根据ID获取租户
+func (s *TenantService) GetTenantByID(id int) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := s.db.First(&tenant, id).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// tenantListSpec 租户列表的搜索、筛选和排序规则
+var tenantListSpec = &listSpec{
+	keywordColumns: []string{"code", "name"},
+	filters: map[string]func(db *gorm.DB, value interface{}) *gorm.DB{
+		"status": eqFilter("status"),
+	},
+	sortColumns: commonSortColumns(map[string]string{"code": "code", "name": "name"}),
+	defaultSort: []string{"id"},
+}
+
+// PageTenants 获取租户列表，支持关键字搜索、按状态筛选和多字段排序
+func (s *TenantService) PageTenants(query ListQuery) ([]models.Tenant, PageInfo, error) {
+	var tenants []models.Tenant
+	info, err := pageQuery(s.db.Model(&models.Tenant{}), query, tenantListSpec, &tenants)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	return tenants, info, nil
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"tenant-center/config"
+	"tenant-center/keyring"
+	"tenant-center/models"
+	"time"
+)
+
+// 令牌相关错误
+var (
+	ErrInvalidToken        = errors.New("无效的token")
+	ErrTokenRevoked        = errors.New("token已失效")
+	ErrInvalidRefreshToken = errors.New("无效的刷新令牌")
+	ErrRefreshTokenReused  = errors.New("刷新令牌已被使用，请重新登录")
+)
+
+// AccessClaims 访问令牌的声明
+type AccessClaims struct {
+	UserID   int    `json:"user_id"`
+	Username string `json:"username"`
+	TenantID int    `json:"tenant_id"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair 登录或刷新后签发的令牌
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期，单位秒
+	TokenType    string `json:"token_type"`
+}
+
+// TokenService 令牌服务，负责签发、校验、刷新和吊销令牌
+type TokenService struct {
+	db        *gorm.DB
+	jwtConfig config.JWTConfig
+	keyring   *keyring.Keyring
+}
+
+// NewTokenService 创建令牌服务实例，访问令牌使用密钥环中的非对称密钥签名
+func NewTokenService(db *gorm.DB, cfg *config.Config, ring *keyring.Keyring) *TokenService {
+	return &TokenService{db: db, jwtConfig: cfg.JWT, keyring: ring}
+}
+
+// WithContext 返回绑定请求上下文的服务实例
+func (s *TokenService) WithContext(ctx context.Context) *TokenService {
+	clone := *s
+	clone.db = s.db.WithContext(ctx)
+	return &clone
+}
+
+// IssueTokens 为用户签发新的访问令牌和刷新令牌，开启新的令牌族
+func (s *TokenService) IssueTokens(user *models.User) (*TokenPair, error) {
+	familyID, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(s.db, user, familyID)
+}
+
+// issue 签发访问令牌，并在指定令牌族下保存新的刷新令牌
+func (s *TokenService) issue(tx *gorm.DB, user *models.User, familyID string) (*TokenPair, error) {
+	jti, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := AccessClaims{
+		UserID:   user.ID,
+		Username: user.Username,
+		TenantID: user.TenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.jwtConfig.Expire.Duration)),
+		},
+	}
+
+	// 使用密钥环中当前生效的密钥签名token
+	accessToken, err := s.keyring.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(raw)
+
+	record := &models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  familyID,
+		ExpiresAt: now.Add(s.jwtConfig.RefreshExpire.Duration),
+	}
+	if err := tx.Create(record).Error; err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwtConfig.Expire.Seconds()),
+		TokenType:    "Bearer",
+	}, nil
+}
+
+// ParseAccessToken 校验访问令牌的签名、有效期和吊销状态
+func (s *TokenService) ParseAccessToken(tokenString string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	// 按头部的 kid 选择公钥，只接受密钥环中使用的签名算法
+	token, err := jwt.ParseWithClaims(tokenString, claims, s.keyring.Keyfunc, jwt.WithValidMethods(s.keyring.ValidMethods()))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	// 早期签发的token没有租户和jti信息，需要重新登录
+	if claims.ID == "" || claims.TenantID == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	var count int64
+	if err := s.db.Model(&models.RevokedToken{}).Where("jti = ?", claims.ID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// Refresh 使用刷新令牌换取新的令牌，旧的刷新令牌随即失效
+func (s *TokenService) Refresh(refreshToken string) (*TokenPair, error) {
+	var pair *TokenPair
+	var reused bool
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var record models.RefreshToken
+		if err := tx.Where("token_hash = ?", hashToken(refreshToken)).First(&record).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		// 已使用或已吊销的令牌再次出现，说明令牌可能泄露，吊销整个令牌族
+		if record.UsedAt != nil || record.RevokedAt != nil {
+			reused = true
+			return ErrRefreshTokenReused
+		}
+
+		if time.Now().After(record.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		// 并发刷新时只有一个请求能标记成功
+		now := time.Now()
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", record.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			reused = true
+			return ErrRefreshTokenReused
+		}
+
+		user, err := loadActiveUser(tx, record.UserID)
+		if err != nil {
+			return err
+		}
+
+		pair, err = s.issue(tx, user, record.FamilyID)
+		return err
+	})
+
+	if reused {
+		if err := s.revokeFamilyByToken(refreshToken); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Logout 吊销当前访问令牌，并吊销刷新令牌所在的令牌族
+func (s *TokenService) Logout(claims *AccessClaims, refreshToken string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		revoked := &models.RevokedToken{
+			JTI:       claims.ID,
+			UserID:    claims.UserID,
+			ExpiresAt: claims.ExpiresAt.Time,
+		}
+		if err := tx.Where(models.RevokedToken{JTI: claims.ID}).FirstOrCreate(revoked).Error; err != nil {
+			return err
+		}
+
+		// 顺带清理已过期的黑名单记录
+		if err := tx.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{}).Error; err != nil {
+			return err
+		}
+
+		if refreshToken == "" {
+			return nil
+		}
+		var record models.RefreshToken
+		if err := tx.Where("token_hash = ? AND user_id = ?", hashToken(refreshToken), claims.UserID).First(&record).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", record.FamilyID).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+// revokeUserTokens 吊销用户的全部刷新令牌，已签发的访问令牌在短有效期后自然失效
+//
+// 在删除用户、移除用户角色的事务中调用，用户须重新登录才能继续使用，避免凭旧会话长期续期。
+func revokeUserTokens(tx *gorm.DB, userID int) error {
+	return tx.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// revokeFamilyByToken 吊销刷新令牌所在的整个令牌族
+func (s *TokenService) revokeFamilyByToken(refreshToken string) error {
+	var record models.RefreshToken
+	if err := s.db.Where("token_hash = ?", hashToken(refreshToken)).First(&record).Error; err != nil {
+		return err
+	}
+	return s.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", record.FamilyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// loadActiveUser 加载用户并检查所属租户是否可用
+func loadActiveUser(db *gorm.DB, userID int) (*models.User, error) {
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	var tenant models.Tenant
+	if err := db.First(&tenant, user.TenantID).Error; err != nil {
+		return nil, errors.New("租户不存在")
+	}
+	if tenant.Status != models.TenantStatusActive {
+		return nil, errors.New("租户已停用")
+	}
+	return &user, nil
+}
+
+// hashToken 计算刷新令牌的SHA-256哈希
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomID 生成128位随机ID的十六进制表示
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,32 +1,69 @@
 package services
 
 import (
-	"encoding/base64"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"github.com/golang-jwt/jwt/v5"
+	"fmt"
 	"gorm.io/gorm"
+	"log"
+	"sort"
+	"strings"
+	"tenant-center/config"
 	"tenant-center/models"
-	"time"
+	"tenant-center/utils"
 )
 
 // UserService 用户服务
 type UserService struct {
-	db *gorm.DB
+	db              *gorm.DB
+	passwordEncoder *utils.PasswordEncoder
+	resolver        *PermissionResolver
 }
 
 // NewUserService 创建用户服务实例
-func NewUserService(db *gorm.DB) *UserService {
-	return &UserService{db: db}
+func NewUserService(db *gorm.DB, cfg *config.Config) *UserService {
+	// 算法名称已在配置校验阶段检查过
+	encoder, err := utils.NewPasswordEncoder(cfg.Password.Algorithm)
+	if err != nil {
+		encoder = utils.DefaultPasswordEncoder()
+	}
+
+	return &UserService{
+		db:              db,
+		passwordEncoder: encoder,
+		resolver:        NewPermissionResolver(db, cfg),
+	}
+}
+
+// WithContext 返回绑定请求上下文的服务实例，数据库操作按上下文中的租户自动隔离
+func (s *UserService) WithContext(ctx context.Context) *UserService {
+	clone := *s
+	clone.db = s.db.WithContext(ctx)
+	clone.resolver = s.resolver.WithContext(ctx)
+	return &clone
 }
 
 // CreateUser 创建用户
 func (s *UserService) CreateUser(user *models.User) error {
-	// 将密码进行了Base64编码，直接存储
-	user.Password = base64.StdEncoding.EncodeToString([]byte(user.Password))
-	return s.db.Create(user).Error
+	hashed, err := s.passwordEncoder.Encode(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashed
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourceUser, user.ID, models.AuditActionCreate, nil, user)
+	})
 }
 
 // UpdateUser 更新用户信息
+//
+// user.Version 为客户端持有的版本，非 0 时与当前版本不一致返回 ErrVersionConflict；更新成功后写回新版本。
 func (s *UserService) UpdateUser(user *models.User) error {
 	// 创建一个map来存储需要更新的字段
 	updates := map[string]interface{}{
@@ -35,46 +72,103 @@ func (s *UserService) UpdateUser(user *models.User) error {
 
 	// 只有当密码不为空时才更新密码
 	if user.Password != "" {
-		updates["password"] = base64.StdEncoding.EncodeToString([]byte(user.Password))
+		hashed, err := s.passwordEncoder.Encode(user.Password)
+		if err != nil {
+			return err
+		}
+		updates["password"] = hashed
 	}
 
-	// 使用 Updates 方法只更新指定字段，让 GORM 自动处理时间戳
-	return s.db.Model(user).Updates(updates).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var before models.User
+		if err := tx.First(&before, user.ID).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(before.Version, user.Version); err != nil {
+			return err
+		}
+
+		// 只更新指定字段并递增版本，让 GORM 自动处理时间戳
+		if err := updateVersioned(tx, user, before.Version, updates); err != nil {
+			return err
+		}
+
+		var after models.User
+		if err := tx.First(&after, user.ID).Error; err != nil {
+			return err
+		}
+		user.Version = after.Version
+		return recordAudit(tx, models.ResourceUser, user.ID, models.AuditActionUpdate, &before, &after)
+	})
 }
 
-// Login 用户登录
-func (s *UserService) Login(username, password string) (string, error) {
+// GetUserByID 根据ID获取用户，不含密码和角色
+func (s *UserService) GetUserByID(id int) (*models.User, error) {
+	var user models.User
+	if err := s.db.Omit("password").First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Authenticate 校验用户名和密码，返回登录用户
+func (s *UserService) Authenticate(username, password string) (*models.User, error) {
 	// 查找用户
 	var user models.User
 	if err := s.db.Where("username = ?", username).First(&user).Error; err != nil {
-		return "", errors.New("用户不存在")
+		return nil, errors.New("用户不存在")
 	}
 
-	// 直接比较Base64编码后的密码
-	if user.Password != password {
-		return "", errors.New("密码错误")
+	ok, needsRehash, err := s.passwordEncoder.Verify(password, user.Password)
+	if err != nil || !ok {
+		return nil, errors.New("密码错误")
 	}
 
-	// 生成JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(), // 24小时过期
-	})
+	// 检查所属租户状态
+	var tenant models.Tenant
+	if err := s.db.First(&tenant, user.TenantID).Error; err != nil {
+		return nil, errors.New("租户不存在")
+	}
+	if tenant.Status != models.TenantStatusActive {
+		return nil, errors.New("租户已停用")
+	}
 
-	// 使用密钥签名token
-	tokenString, err := token.SignedString([]byte("your-secret-key")) // 注意：在实际应用中，密钥应该从配置文件中读取
-	if err != nil {
-		return "", err
+	// 历史的Base64密码或旧算法的哈希值，登录成功后透明迁移到当前算法
+	if needsRehash {
+		if hashed, err := s.passwordEncoder.Encode(password); err == nil {
+			if err := s.db.Model(&user).Update("password", hashed).Error; err != nil {
+				log.Printf("迁移用户 %d 的密码哈希失败: %v", user.ID, err)
+			}
+		}
 	}
 
-	return tokenString, nil
+	return &user, nil
 }
 
-// BindUserRoles 为用户绑定角色
-func (s *UserService) BindUserRoles(userID int, roleIDs []int) error {
+// BindUserRoles 用给定的角色替换用户当前绑定的全部角色，移除了原有角色时吊销用户的刷新令牌
+//
+// version 为客户端持有的用户版本，非 0 时与当前版本不一致返回 ErrVersionConflict，绑定变更后用户版本加一。
+func (s *UserService) BindUserRoles(userID, version int, roleIDs []int) error {
 	// 开启事务
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		// 校验用户和角色属于当前租户，user_role 表本身不带租户信息
+		var user models.User
+		if err := tx.Select("id, version").First(&user, userID).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(user.Version, version); err != nil {
+			return err
+		}
+		roleIDs = uniqueInts(roleIDs)
+		if err := checkIDsExist(tx, &models.Role{}, roleIDs, ErrRoleNotFound); err != nil {
+			return err
+		}
+
+		var before []int
+		if err := tx.Table("user_role").Where("user_id = ?", userID).Order("role_id").Pluck("role_id", &before).Error; err != nil {
+			return err
+		}
+
 		// 先删除用户现有的所有角色
 		if err := tx.Exec("DELETE FROM user_role WHERE user_id = ?", userID).Error; err != nil {
 			return err
@@ -87,7 +181,130 @@ func (s *UserService) BindUserRoles(userID int, roleIDs []int) error {
 			}
 		}
 
-		return nil
+		if err := bumpVersion(tx, &models.User{}, userID, version); err != nil {
+			return err
+		}
+		if err := s.resolver.withDB(tx).refreshUsers([]int{userID}); err != nil {
+			return err
+		}
+
+		after := append([]int(nil), roleIDs...)
+		sort.Ints(after)
+		// 移除了角色时吊销刷新令牌，使用户尽快以新的角色重新登录
+		if removedAny(before, after) {
+			if err := revokeUserTokens(tx, userID); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, models.ResourceUser, userID, models.AuditActionBindRoles, before, after)
+	})
+}
+
+// PatchUserRoles 为用户增量添加、移除角色，返回修改后用户的全部角色ID，移除了角色时吊销用户的刷新令牌
+//
+// 添加已绑定的角色、移除未绑定的角色均不报错，重复调用结果相同；用户不存在时返回 gorm.ErrRecordNotFound。
+// version 的校验同 BindUserRoles，绑定没有变化时版本不变。
+func (s *UserService) PatchUserRoles(userID, version int, add, remove []int) ([]int, error) {
+	var after []int
+	err := s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		// 校验用户和角色属于当前租户，user_role 表本身不带租户信息
+		var user models.User
+		if err := tx.Select("id, version").First(&user, userID).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(user.Version, version); err != nil {
+			return err
+		}
+		if err := checkAddRemove(add, remove); err != nil {
+			return err
+		}
+		if err := checkIDsExist(tx, &models.Role{}, append(append([]int(nil), add...), remove...), ErrRoleNotFound); err != nil {
+			return err
+		}
+
+		var before []int
+		if err := tx.Table("user_role").Where("user_id = ?", userID).Order("role_id").Pluck("role_id", &before).Error; err != nil {
+			return err
+		}
+		bound := make(map[int]bool, len(before))
+		for _, roleID := range before {
+			bound[roleID] = true
+		}
+
+		changed, removed := false, false
+		for _, roleID := range uniqueInts(add) {
+			if bound[roleID] {
+				continue
+			}
+			if err := tx.Exec("INSERT INTO user_role (user_id, role_id) VALUES (?, ?)", userID, roleID).Error; err != nil {
+				return err
+			}
+			bound[roleID] = true
+			changed = true
+		}
+		for _, roleID := range uniqueInts(remove) {
+			if !bound[roleID] {
+				continue
+			}
+			if err := tx.Exec("DELETE FROM user_role WHERE user_id = ? AND role_id = ?", userID, roleID).Error; err != nil {
+				return err
+			}
+			delete(bound, roleID)
+			changed, removed = true, true
+		}
+
+		after = make([]int, 0, len(bound))
+		for roleID := range bound {
+			after = append(after, roleID)
+		}
+		sort.Ints(after)
+		if !changed {
+			return nil
+		}
+		if err := bumpVersion(tx, &models.User{}, userID, version); err != nil {
+			return err
+		}
+		if err := s.resolver.withDB(tx).refreshUsers([]int{userID}); err != nil {
+			return err
+		}
+		if removed {
+			if err := revokeUserTokens(tx, userID); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, models.ResourceUser, userID, models.AuditActionBindRoles, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// DeleteUser 将用户移入回收站，同时摘下用户的角色关联并吊销其刷新令牌
+func (s *UserService) DeleteUser(userID int) error {
+	return s.resolver.transaction(s.db, func(tx *gorm.DB) error {
+		// 校验用户属于当前租户
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		if err := detachBindings(tx, models.ResourceUser, []int{userID}); err != nil {
+			return err
+		}
+
+		if err := revokeUserTokens(tx, userID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.User{}, userID).Error; err != nil {
+			return err
+		}
+		// 已删除的用户只清除投影
+		if err := s.resolver.withDB(tx).refreshUsers([]int{userID}); err != nil {
+			return err
+		}
+		return recordAudit(tx, models.ResourceUser, userID, models.AuditActionDelete, &user, nil)
 	})
 }
 
@@ -110,49 +327,163 @@ type RouteMeta struct {
 	Authority  []int  `json:"authority,omitempty"`
 }
 
-// PageUsers 获取用户列表，支持分页
-func (s *UserService) PageUsers(page, pageSize int) ([]models.User, int64, error) {
+// userListSpec 用户列表的搜索、筛选和排序规则
+var userListSpec = &listSpec{
+	keywordColumns: []string{"username"},
+	filters: map[string]func(db *gorm.DB, value interface{}) *gorm.DB{
+		"role_id": func(db *gorm.DB, value interface{}) *gorm.DB {
+			return db.Where("id IN (SELECT user_id FROM user_role WHERE role_id = ?)", value)
+		},
+	},
+	sortColumns: commonSortColumns(map[string]string{"username": "username"}),
+	defaultSort: []string{"id"},
+}
+
+// PageUsers 获取用户列表，支持关键字搜索、按角色和创建时间筛选以及多字段排序
+func (s *UserService) PageUsers(query ListQuery) ([]models.User, PageInfo, error) {
 	var users []models.User
-	var total int64
+	// 排除密码字段
+	info, err := pageQuery(s.db.Model(&models.User{}).Omit("password"), query, userListSpec, &users)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	return users, info, nil
+}
+
+// UserPermissions 用户生效的菜单和按钮权限编码
+type UserPermissions struct {
+	Codes      []string `json:"codes" example:"user:create,user:delete"` // 按编码排序，通配权限已展开为具体编码
+	SuperAdmin bool     `json:"super_admin" example:"false"`             // 超级管理员不受权限限制，前端应放行全部按钮
+	Version    string   `json:"version" example:"9f86d081884c7d65"`      // 权限编码的版本，与响应头 ETag 一致，编码不变时版本不变
+}
 
-	// 计算总记录数
-	if err := s.db.Model(&models.User{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+// GetUserPermissions 获取用户生效的菜单和按钮权限编码，超级管理员返回当前租户的全部权限编码
+func (s *UserService) GetUserPermissions(userID int) (*UserPermissions, error) {
+	isSuper, err := s.resolver.IsSuperAdmin(userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// 获取分页数据，排除密码字段
-	offset := (page - 1) * pageSize
-	if err := s.db.Select("id, username, created_at, updated_at").Offset(offset).Limit(pageSize).Find(&users).Error; err != nil {
-		return nil, 0, err
+	var codes []string
+	if isSuper {
+		if err := s.db.Model(&models.Permission{}).Where("type IN ?", []string{"menu", "button"}).Pluck("code", &codes).Error; err != nil {
+			return nil, err
+		}
+	} else if codes, err = s.resolver.GetUserPermissionCodes(userID); err != nil {
+		return nil, err
 	}
 
-	return users, total, nil
+	result := &UserPermissions{Codes: make([]string, 0, len(codes)), SuperAdmin: isSuper}
+	for _, code := range codes {
+		if !isWildcardCode(code) {
+			result.Codes = append(result.Codes, code)
+		}
+	}
+	sort.Strings(result.Codes)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%t\n%s", isSuper, strings.Join(result.Codes, "\n"))
+	result.Version = hex.EncodeToString(hash.Sum(nil))[:16]
+	return result, nil
 }
 
-// GetUserRoutes 获取用户的路由数据
+// GetUserRoutes 获取用户的路由数据，只包含用户角色通过菜单权限可访问的菜单及其上级菜单
+//
+// 启用权限缓存时路由树按用户缓存，菜单和授权数据变化后重新构建。
 func (s *UserService) GetUserRoutes(userID int) ([]RouteItem, error) {
+	value, err := s.resolver.cached(userID, cachedRoutes, func() (interface{}, error) {
+		return s.loadUserRoutes(userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]RouteItem), nil
+}
+
+// loadUserRoutes 查询菜单和用户的菜单授权，构建路由树
+func (s *UserService) loadUserRoutes(userID int) ([]RouteItem, error) {
 	// 获取用户的角色
 	var user models.User
 	if err := s.db.Preload("Roles").First(&user, userID).Error; err != nil {
 		return nil, err
 	}
 
-	// 获取所有菜单
+	// 获取所有可见菜单
 	var menus []models.Menu
-	if err := s.db.Order("parent_id, `order`").Find(&menus).Error; err != nil {
+	if err := s.db.Where("is_visible = ?", true).Order("parent_id, `order`").Find(&menus).Error; err != nil {
 		return nil, err
 	}
 
+	isSuper, err := s.resolver.IsSuperAdmin(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 计算每个菜单的授权角色
+	authority := make(map[int]map[int]bool)
+	if isSuper {
+		// 超级管理员可访问全部菜单
+		for _, menu := range menus {
+			authority[menu.ID] = make(map[int]bool)
+			for _, role := range user.Roles {
+				authority[menu.ID][role.ID] = true
+			}
+		}
+	} else {
+		grants, err := s.resolver.GetUserMenuGrants(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		menuMap := make(map[int]models.Menu, len(menus))
+		for _, menu := range menus {
+			menuMap[menu.ID] = menu
+		}
+
+		// 授权菜单的上级菜单自动保留，并继承子菜单的授权角色
+		for menuID, roleIDs := range grants {
+			visited := make(map[int]bool)
+			for id := menuID; id != 0 && !visited[id]; {
+				visited[id] = true
+				menu, ok := menuMap[id]
+				if !ok {
+					break
+				}
+				if authority[id] == nil {
+					authority[id] = make(map[int]bool)
+				}
+				for _, roleID := range roleIDs {
+					authority[id][roleID] = true
+				}
+				if menu.ParentID == nil {
+					break
+				}
+				id = *menu.ParentID
+			}
+		}
+	}
+
 	// 构建菜单树
-	return s.buildRouteTree(menus, 0), nil
+	return s.buildRouteTree(menus, 0, authority), nil
 }
 
-// buildRouteTree 构建路由树
-func (s *UserService) buildRouteTree(menus []models.Menu, parentID int) []RouteItem {
+// buildRouteTree 构建路由树，authority 中不存在的菜单不会出现在结果中
+func (s *UserService) buildRouteTree(menus []models.Menu, parentID int, authority map[int]map[int]bool) []RouteItem {
 	var routes []RouteItem
 
 	for _, menu := range menus {
+		roleSet, granted := authority[menu.ID]
+		if !granted {
+			continue
+		}
+
 		if menu.ParentID == nil && parentID == 0 || (menu.ParentID != nil && *menu.ParentID == parentID) {
+			roleIDs := make([]int, 0, len(roleSet))
+			for roleID := range roleSet {
+				roleIDs = append(roleIDs, roleID)
+			}
+			sort.Ints(roleIDs)
+
 			route := RouteItem{
 				Component: menu.Component,
 				Name:      menu.Name,
@@ -163,12 +494,12 @@ func (s *UserService) buildRouteTree(menus []models.Menu, parentID int) []RouteI
 					DarkIcon:   menu.Icon, // 可以根据需要设置不同的图标
 					ActiveIcon: menu.Icon,
 					Order:      menu.Order,
-					Authority:  []int{1}, // 这里可以根据实际权限设置
+					Authority:  roleIDs,
 				},
 			}
 
 			// 递归获取子菜单
-			children := s.buildRouteTree(menus, menu.ID)
+			children := s.buildRouteTree(menus, menu.ID, authority)
 			if len(children) > 0 {
 				route.Children = children
 			}
@@ -0,0 +1,14 @@
+package services
+
+// uniqueInts 去除重复的ID，保持原有顺序
+func uniqueInts(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict 资源已被他人修改，客户端持有的版本已过期
+var ErrVersionConflict = errors.New("资源已被修改，请刷新后重试")
+
+// checkVersion 校验客户端持有的版本与当前版本一致，expected 为 0 表示不校验
+func checkVersion(current, expected int) error {
+	if expected != 0 && expected != current {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// updateVersioned 在版本仍为 current 时更新字段并将版本加一
+//
+// 读取 current 之后若有其他事务先完成了修改，条件更新不会命中任何行，返回 ErrVersionConflict。
+func updateVersioned(tx *gorm.DB, model interface{}, current int, updates map[string]interface{}) error {
+	updates["version"] = gorm.Expr("version + 1")
+	result := tx.Model(model).Where("version = ?", current).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// bumpVersion 递增资源版本，用于修改资源的关联关系；expected 非 0 时要求版本仍为 expected，否则返回 ErrVersionConflict
+func bumpVersion(tx *gorm.DB, model interface{}, id, expected int) error {
+	query := tx.Model(model).Where("id = ?", id)
+	if expected != 0 {
+		query = query.Where("version = ?", expected)
+	}
+	result := query.UpdateColumn("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
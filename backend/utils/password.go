@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 支持的密码哈希算法
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// PasswordHasher 密码哈希算法接口
+type PasswordHasher interface {
+	// Name 算法名称，同时作为存储值的前缀，例如 {bcrypt}
+	Name() string
+	// Hash 对明文密码进行哈希
+	Hash(password string) (string, error)
+	// Verify 校验明文密码与哈希值是否匹配
+	Verify(password, hashed string) (bool, error)
+}
+
+// PasswordEncoder 密码编码器，按前缀将哈希值分发给对应的算法
+//
+// 存储格式为 {算法}哈希值，没有前缀的值视为历史遗留的Base64编码密码
+type PasswordEncoder struct {
+	defaultHasher PasswordHasher
+	hashers       map[string]PasswordHasher
+}
+
+// NewPasswordEncoder 创建密码编码器，algorithm 为新密码使用的算法
+func NewPasswordEncoder(algorithm string) (*PasswordEncoder, error) {
+	e := &PasswordEncoder{hashers: make(map[string]PasswordHasher)}
+	e.Register(NewBcryptHasher(bcrypt.DefaultCost))
+	e.Register(NewArgon2idHasher())
+
+	hasher, ok := e.hashers[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("不支持的密码哈希算法: %s", algorithm)
+	}
+	e.defaultHasher = hasher
+	return e, nil
+}
+
+// DefaultPasswordEncoder 创建使用bcrypt算法的密码编码器
+func DefaultPasswordEncoder() *PasswordEncoder {
+	e, _ := NewPasswordEncoder(AlgorithmBcrypt)
+	return e
+}
+
+// Register 注册密码哈希算法，同名算法会被覆盖
+func (e *PasswordEncoder) Register(hasher PasswordHasher) {
+	e.hashers[hasher.Name()] = hasher
+}
+
+// Encode 使用默认算法对密码进行哈希，返回带算法前缀的值
+func (e *PasswordEncoder) Encode(password string) (string, error) {
+	hashed, err := e.defaultHasher.Hash(password)
+	if err != nil {
+		return "", err
+	}
+	return "{" + e.defaultHasher.Name() + "}" + hashed, nil
+}
+
+// Verify 校验密码，needsRehash 表示存储值应使用默认算法重新哈希
+func (e *PasswordEncoder) Verify(password, stored string) (ok bool, needsRehash bool, err error) {
+	algorithm, hashed, hasPrefix := splitAlgorithm(stored)
+	if !hasPrefix {
+		// 历史数据：直接存储的Base64编码密码
+		expected := base64.StdEncoding.EncodeToString([]byte(password))
+		ok = subtle.ConstantTimeCompare([]byte(expected), []byte(stored)) == 1
+		return ok, ok, nil
+	}
+
+	hasher, exists := e.hashers[algorithm]
+	if !exists {
+		return false, false, fmt.Errorf("不支持的密码哈希算法: %s", algorithm)
+	}
+
+	ok, err = hasher.Verify(password, hashed)
+	if err != nil || !ok {
+		return false, false, err
+	}
+	return true, algorithm != e.defaultHasher.Name(), nil
+}
+
+// splitAlgorithm 拆分 {算法}哈希值 格式的存储值
+func splitAlgorithm(stored string) (string, string, bool) {
+	if !strings.HasPrefix(stored, "{") {
+		return "", "", false
+	}
+	end := strings.Index(stored, "}")
+	if end < 0 {
+		return "", "", false
+	}
+	return stored[1:end], stored[end+1:], true
+}
+
+// BcryptHasher bcrypt算法
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher 创建bcrypt算法实例
+func NewBcryptHasher(cost int) *BcryptHasher {
+	return &BcryptHasher{cost: cost}
+}
+
+// Name 算法名称
+func (h *BcryptHasher) Name() string {
+	return AlgorithmBcrypt
+}
+
+// Hash 对明文密码进行哈希
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Verify 校验明文密码与哈希值是否匹配
+func (h *BcryptHasher) Verify(password, hashed string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Argon2idHasher argon2id算法，哈希值采用PHC字符串格式
+type Argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  uint32
+	keyLength   uint32
+}
+
+// NewArgon2idHasher 创建argon2id算法实例，参数参考 RFC 9106 的推荐值
+func NewArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{
+		memory:      64 * 1024,
+		iterations:  3,
+		parallelism: 2,
+		saltLength:  16,
+		keyLength:   32,
+	}
+}
+
+// Name 算法名称
+func (h *Argon2idHasher) Name() string {
+	return AlgorithmArgon2id
+}
+
+// Hash 对明文密码进行哈希
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, h.keyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.memory, h.iterations, h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify 校验明文密码与哈希值是否匹配
+func (h *Argon2idHasher) Verify(password, hashed string) (bool, error) {
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return false, errors.New("无效的argon2id哈希格式")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, errors.New("不支持的argon2id版本")
+	}
+
+	var memory, iterations uint32
+	var parallelism uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism); err != nil {
+		return false, errors.New("无效的argon2id参数")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, errors.New("无效的argon2id盐值")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, errors.New("无效的argon2id哈希值")
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, actual) == 1, nil
+}
@@ -0,0 +1,19 @@
+import type { ReactNode } from 'react';
+import useUserStore from '@/store/userStore';
+
+interface AuthorizedProps {
+  // 需要的权限编码，传数组时拥有其中任一即可
+  code: string | string[];
+  children: ReactNode;
+  // 无权限时显示的内容，默认不显示
+  fallback?: ReactNode;
+}
+
+// 按当前用户的权限编码控制按钮等元素的显示，权限刷新后自动重新渲染
+const Authorized = ({ code, children, fallback = null }: AuthorizedProps) => {
+  const codes = Array.isArray(code) ? code : [code];
+  const allowed = useUserStore((state) => codes.some((c) => state.hasPermission(c)));
+  return <>{allowed ? children : fallback}</>;
+};
+
+export default Authorized;
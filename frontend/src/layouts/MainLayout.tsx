@@ -8,6 +8,8 @@ import PermissionList from '../pages/permissions/PermissionList';
 import MenuList from '../pages/menus/MenuList';
 import ButtonList from '../pages/buttons/ButtonList';
 import Login from '../pages/login/Login';
+import request from '../utils/request';
+import useUserStore from '../store/userStore';
 
 const { Header, Sider, Content } = Layout;
 
@@ -28,10 +30,24 @@ const MainLayout = () => {
     }
   }, [isAuthenticated, location.pathname, navigate]);
 
-  const handleLogout = () => {
-    localStorage.removeItem('token');
-    localStorage.removeItem('username');
-    navigate('/login');
+  // 登录后加载按钮权限编码，用于控制页面中按钮的显示
+  const fetchUserPermissions = useUserStore((state) => state.fetchUserPermissions);
+  useEffect(() => {
+    if (isAuthenticated) {
+      fetchUserPermissions().catch(() => {});
+    }
+  }, [isAuthenticated, fetchUserPermissions]);
+
+  const handleLogout = async () => {
+    try {
+      await request.post('/logout', { refresh_token: localStorage.getItem('refresh_token') });
+    } finally {
+      localStorage.removeItem('token');
+      localStorage.removeItem('refresh_token');
+      localStorage.removeItem('username');
+      useUserStore.getState().logout();
+      navigate('/login');
+    }
   };
 
   // 如果在登录页面，不显示布局
@@ -2,6 +2,7 @@ import { useState, useEffect } from 'react';
 import { Table, Button, Space, Modal, Form, Input, Select, message } from 'antd';
 import { PlusOutlined, EditOutlined, DeleteOutlined } from '@ant-design/icons';
 import request from '@/utils/request';
+import Authorized from '@/components/Authorized';
 import type { ColumnsType } from 'antd/es/table';
 
 interface SelectOption {
@@ -166,21 +167,25 @@ const ButtonList = () => {
       key: 'action',
       render: (_, record) => (
         <Space size="middle">
-          <Button
-            type="text"
-            icon={<EditOutlined />}
-            onClick={() => handleEdit(record)}
-          >
-            编辑
-          </Button>
-          <Button
-            type="text"
-            danger
-            icon={<DeleteOutlined />}
-            onClick={() => handleDelete(record.id)}
-          >
-            删除
-          </Button>
+          <Authorized code="button:update">
+            <Button
+              type="text"
+              icon={<EditOutlined />}
+              onClick={() => handleEdit(record)}
+            >
+              编辑
+            </Button>
+          </Authorized>
+          <Authorized code="button:delete">
+            <Button
+              type="text"
+              danger
+              icon={<DeleteOutlined />}
+              onClick={() => handleDelete(record.id)}
+            >
+              删除
+            </Button>
+          </Authorized>
         </Space>
       ),
     },
@@ -189,9 +194,11 @@ const ButtonList = () => {
   return (
     <div>
       <div style={{ marginBottom: 16 }}>
-        <Button type="primary" icon={<PlusOutlined />} onClick={handleAdd}>
-          新增按钮
-        </Button>
+        <Authorized code="button:create">
+          <Button type="primary" icon={<PlusOutlined />} onClick={handleAdd}>
+            新增按钮
+          </Button>
+        </Authorized>
       </div>
       <Table
         columns={columns}
@@ -16,13 +16,10 @@ const Login = () => {
   const onFinish = async (values: LoginForm) => {
     setLoading(true);
     try {
-      const encryptedValues = {
-        ...values,
-        password: btoa(values.password)
-      };
-      const response = await request.post('/login', encryptedValues);
+      const response = await request.post('/login', values);
       if (response.token) {
         localStorage.setItem('token', response.token);
+        localStorage.setItem('refresh_token', response.refresh_token);
         message.success('登录成功');
         navigate('/');
       } else {
@@ -1,9 +1,27 @@
 import { create } from 'zustand';
 import request from '@/utils/request';
 
+// 权限编码缓存在 localStorage 中，按版本向服务端确认，未变化时服务端返回 304
+const PERMISSIONS_CACHE_KEY = 'permissions';
+
+interface PermissionsCache {
+  codes: string[];
+  super_admin: boolean;
+  version: string;
+}
+
+const loadPermissionsCache = (): PermissionsCache | null => {
+  try {
+    return JSON.parse(localStorage.getItem(PERMISSIONS_CACHE_KEY) || 'null');
+  } catch {
+    return null;
+  }
+};
+
 interface UserState {
   user: any;
   permissions: string[];
+  superAdmin: boolean;
   menus: any[];
   setUser: (user: any) => void;
   setPermissions: (permissions: string[]) => void;
@@ -13,11 +31,13 @@ interface UserState {
   fetchUserInfo: () => Promise<void>;
   fetchUserPermissions: () => Promise<void>;
   fetchUserMenus: () => Promise<void>;
+  hasPermission: (code: string) => boolean;
 }
 
-const useUserStore = create<UserState>((set) => ({
+const useUserStore = create<UserState>((set, get) => ({
   user: null,
-  permissions: [],
+  permissions: loadPermissionsCache()?.codes ?? [],
+  superAdmin: loadPermissionsCache()?.super_admin ?? false,
   menus: [],
 
   setUser: (user) => set({ user }),
@@ -36,7 +56,8 @@ const useUserStore = create<UserState>((set) => ({
 
   logout: () => {
     localStorage.removeItem('token');
-    set({ user: null, permissions: [], menus: [] });
+    localStorage.removeItem(PERMISSIONS_CACHE_KEY);
+    set({ user: null, permissions: [], superAdmin: false, menus: [] });
   },
 
   fetchUserInfo: async () => {
@@ -49,12 +70,18 @@ const useUserStore = create<UserState>((set) => ({
   },
 
   fetchUserPermissions: async () => {
-    try {
-      const permissions = await request.get('/users/permissions');
-      set({ permissions });
-    } catch (error) {
-      throw error;
+    const cache = loadPermissionsCache();
+    const data = await request.get<PermissionsCache, PermissionsCache | ''>('/users/me/permissions', {
+      headers: cache ? { 'If-None-Match': `"${cache.version}"` } : {},
+      validateStatus: (status) => status === 200 || status === 304,
+    });
+    // 304 时响应体为空，沿用缓存
+    const permissions = data || cache;
+    if (!permissions) {
+      return;
     }
+    localStorage.setItem(PERMISSIONS_CACHE_KEY, JSON.stringify(permissions));
+    set({ permissions: permissions.codes, superAdmin: permissions.super_admin });
   },
 
   fetchUserMenus: async () => {
@@ -65,6 +92,8 @@ const useUserStore = create<UserState>((set) => ({
       throw error;
     }
   },
+
+  hasPermission: (code) => get().superAdmin || get().permissions.includes(code),
 }));
 
 export default useUserStore;
\ No newline at end of file
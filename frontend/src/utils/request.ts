@@ -23,12 +23,45 @@ request.interceptors.request.use(
   }
 );
 
+// 正在进行的刷新请求，多个请求同时401时共用同一次刷新
+let refreshing: Promise<string> | null = null;
+
+const refreshToken = (): Promise<string> => {
+  if (!refreshing) {
+    refreshing = axios
+      .post('/api/token/refresh', { refresh_token: localStorage.getItem('refresh_token') })
+      .then(({ data }) => {
+        localStorage.setItem('token', data.token);
+        localStorage.setItem('refresh_token', data.refresh_token);
+        return data.token as string;
+      })
+      .finally(() => {
+        refreshing = null;
+      });
+  }
+  return refreshing;
+};
+
 // 响应拦截器
 request.interceptors.response.use(
   (response) => {
     return response.data;
   },
-  (error) => {
+  async (error) => {
+    const config = error.config;
+    // 访问令牌过期时使用刷新令牌换取新令牌后重试一次
+    if (error.response?.status === 401 && config && !config._retry && localStorage.getItem('refresh_token')) {
+      config._retry = true;
+      try {
+        const token = await refreshToken();
+        config.headers.Authorization = `Bearer ${token}`;
+        return request(config);
+      } catch {
+        localStorage.removeItem('token');
+        localStorage.removeItem('refresh_token');
+      }
+    }
+
     if (error.response) {
       const { status } = error.response;
       switch (status) {